@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"regexp"
@@ -14,10 +15,6 @@ import (
 	"aliasly/internal/config"
 )
 
-// namePattern validates alias names.
-// Alias names can only contain letters, numbers, and hyphens.
-var namePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]*$`)
-
 // addCmd represents the add command.
 // It interactively guides the user through creating a new alias.
 var addCmd = &cobra.Command{
@@ -37,21 +34,86 @@ You will be asked to provide:
   - Alias name (short name you'll type, e.g., "gs")
   - Command to run (the full command, e.g., "git status")
   - Description (optional, helps you remember what it does)
+  - Working directory (optional, where the command should run)
   - Parameters (optional, for commands that need input)
 
 For parameterized commands, use {{name}} syntax in your command:
   git commit -am "{{message}}"
 
+Commands can also span several lines, e.g. to set a variable and use it
+on the next line or to include a here-doc. Write them in the config file
+with a YAML block scalar (command: |) or with 'al edit'; the whole text
+is passed to the shell as one script.
+
+Use --no-save to preview the alias without writing it to your config.
+
+If the name matches a command in your $PATH (like 'ls'), a warning is
+printed, since it can be confusing if your shell also aliases it. The
+alias is still created; pass --no-warn to skip the check.
+
+To create an alias without prompts (e.g. in a bootstrap script), give
+at least --name and --command. Each --param declares a parameter as
+name, name:required, name:optional or name:optional:default;
+placeholders in the command without a --param are required.
+
 Examples:
-  al add     # Start interactive alias creation
-  al new     # Same as above`,
+  al add                   # Start interactive alias creation
+  al new                   # Same as above
+  al add --no-save --json  # Preview the alias as JSON without saving
+  al add --name gs --command "git status" --description "Show status"
+  al add --name gp --command "git push origin {{branch}}" \
+         --param branch:optional:main --tag git`,
 
 	// Run function
 	Run: runAddCmd,
 }
 
+// addNoSaveFlag, when true, validates and previews the alias without saving it
+var addNoSaveFlag bool
+
+// addJSONFlag, when true, prints the resulting alias as JSON
+var addJSONFlag bool
+
+// addNoWarnFlag, when true, skips the warning for names that match a command in $PATH
+var addNoWarnFlag bool
+
+// addNameFlag, addCommandFlag, addDescriptionFlag, addWorkingDirFlag,
+// addTagsFlag and addParamFlags create the alias without prompts
+// (--name and --command are required for that)
+var (
+	addNameFlag        string
+	addCommandFlag     string
+	addDescriptionFlag string
+	addWorkingDirFlag  string
+	addTagsFlag        []string
+	addParamFlags      []string
+)
+
+func init() {
+	addCmd.Flags().BoolVar(&addNoSaveFlag, "no-save", false, "Preview the alias without saving it")
+	addCmd.Flags().BoolVar(&addJSONFlag, "json", false, "Print the resulting alias as JSON")
+	addCmd.Flags().BoolVar(&addNoWarnFlag, "no-warn", false, "Don't warn if the name matches a command in $PATH")
+	addCmd.Flags().StringVar(&addNameFlag, "name", "", "Alias name (with --command, skips the prompts)")
+	addCmd.Flags().StringVar(&addCommandFlag, "command", "", "Command to run (with --name, skips the prompts)")
+	addCmd.Flags().StringVar(&addDescriptionFlag, "description", "", "Description of the alias")
+	addCmd.Flags().StringVar(&addWorkingDirFlag, "working-dir", "", "Directory to run the command in")
+	addCmd.Flags().StringSliceVar(&addTagsFlag, "tag", nil, "Tag for the alias (repeatable or comma-separated)")
+	addCmd.Flags().StringArrayVar(&addParamFlags, "param", nil, "Parameter as name[:required|:optional[:default]] (repeatable)")
+}
+
 // runAddCmd executes the add command.
 func runAddCmd(cmd *cobra.Command, args []string) {
+	// Create the alias from flags if they were given
+	if addNameFlag != "" || addCommandFlag != "" {
+		newAlias, err := aliasFromFlags()
+		if err != nil {
+			printError(err.Error())
+			os.Exit(1)
+		}
+		saveNewAlias(newAlias)
+		return
+	}
+
 	fmt.Println("Create a new alias")
 	fmt.Println("------------------")
 	fmt.Println()
@@ -77,7 +139,21 @@ func runAddCmd(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	// Step 4: Get parameters (if any {{placeholders}} in command)
+	// Step 4: Get working directory
+	workingDir, err := promptWorkingDir()
+	if err != nil {
+		handlePromptError(err)
+		return
+	}
+
+	// Step 5: Get tags
+	tags, err := promptTags()
+	if err != nil {
+		handlePromptError(err)
+		return
+	}
+
+	// Step 6: Get parameters (if any {{placeholders}} in command)
 	params, err := promptParams(command)
 	if err != nil {
 		handlePromptError(err)
@@ -90,6 +166,29 @@ func runAddCmd(cmd *cobra.Command, args []string) {
 		Command:     command,
 		Description: description,
 		Params:      params,
+		WorkingDir:  workingDir,
+		Tags:        tags,
+	}
+
+	saveNewAlias(newAlias)
+}
+
+// saveNewAlias saves a newly created alias and reports the result,
+// or only previews it with --no-save.
+func saveNewAlias(newAlias config.Alias) {
+	name := newAlias.Name
+
+	// In preview mode, stop before anything is written to disk
+	if addNoSaveFlag {
+		fmt.Println()
+		if addJSONFlag {
+			printAliasJSON(newAlias)
+			return
+		}
+		fmt.Printf("[no-save] Would create alias '%s'\n", name)
+		fmt.Printf("Usage: al %s\n", alias.BuildUsageString(newAlias))
+		warnShadowedCommand(name)
+		return
 	}
 
 	// Save the alias
@@ -98,12 +197,130 @@ func runAddCmd(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if addJSONFlag {
+		printAliasJSON(newAlias)
+		return
+	}
+
 	// Success message
-	fmt.Println()
-	green := color.New(color.FgGreen, color.Bold)
-	green.Printf("Alias '%s' created successfully!\n", name)
-	fmt.Println()
-	fmt.Printf("Usage: al %s\n", alias.BuildUsageString(newAlias))
+	if !quietFlag {
+		fmt.Println()
+		green := color.New(color.FgGreen, color.Bold)
+		green.Printf("Alias '%s' created successfully!\n", name)
+		fmt.Println()
+		fmt.Printf("Usage: al %s\n", alias.BuildUsageString(newAlias))
+	}
+	warnUnusedParams(newAlias)
+	warnShadowedCommand(name)
+}
+
+// warnShadowedCommand prints a warning if name matches a command in
+// $PATH, unless --no-warn was given.
+func warnShadowedCommand(name string) {
+	if addNoWarnFlag {
+		return
+	}
+	if path, ok := alias.ShadowedCommand(name); ok {
+		yellow := color.New(color.FgYellow)
+		yellow.Fprintf(os.Stderr, "Warning: '%s' is also a command in your $PATH (%s)\n", name, path)
+	}
+}
+
+// aliasFromFlags builds an alias from the --name, --command and related
+// flags, validating it the same way the prompts do.
+// Placeholders in the command without a --param become required params.
+func aliasFromFlags() (config.Alias, error) {
+	if addNameFlag == "" || strings.TrimSpace(addCommandFlag) == "" {
+		return config.Alias{}, fmt.Errorf("--name and --command must be given together to add an alias without prompts")
+	}
+
+	if err := alias.ValidateName(addNameFlag); err != nil {
+		return config.Alias{}, err
+	}
+	if _, exists := alias.Find(addNameFlag); exists {
+		return config.Alias{}, fmt.Errorf("alias '%s' already exists", addNameFlag)
+	}
+
+	declared := make(map[string]config.Param)
+	for _, spec := range addParamFlags {
+		param, err := parseParamFlag(spec)
+		if err != nil {
+			return config.Alias{}, err
+		}
+		if _, dup := declared[param.Name]; dup {
+			return config.Alias{}, fmt.Errorf("parameter '%s' is given more than once", param.Name)
+		}
+		declared[param.Name] = param
+	}
+
+	// Params are positional, so order them as they appear in the command
+	var params []config.Param
+	added := make(map[string]bool)
+	for _, name := range alias.ExtractPlaceholders(addCommandFlag) {
+		if added[name] {
+			continue
+		}
+		added[name] = true
+
+		param, ok := declared[name]
+		if !ok {
+			param = config.Param{Name: name, Required: true}
+		}
+		params = append(params, param)
+	}
+
+	// Keep params the command doesn't use, so they're warned about
+	for _, spec := range addParamFlags {
+		name, _, _ := strings.Cut(spec, ":")
+		if !added[name] {
+			added[name] = true
+			params = append(params, declared[name])
+		}
+	}
+
+	a := config.Alias{
+		Name:        addNameFlag,
+		Command:     addCommandFlag,
+		Description: addDescriptionFlag,
+		Params:      params,
+		WorkingDir:  addWorkingDirFlag,
+		Tags:        addTagsFlag,
+	}
+
+	if err := alias.ValidateVariadic(a); err != nil {
+		return config.Alias{}, err
+	}
+
+	return a, nil
+}
+
+// parseParamFlag parses a --param value: name, name:required,
+// name:optional or name:optional:default. The default may contain colons.
+func parseParamFlag(spec string) (config.Param, error) {
+	parts := strings.SplitN(spec, ":", 3)
+	param := config.Param{Name: parts[0], Required: true}
+
+	if param.Name == "" {
+		return config.Param{}, fmt.Errorf("invalid --param '%s': missing parameter name", spec)
+	}
+
+	if len(parts) > 1 {
+		switch parts[1] {
+		case "required":
+			if len(parts) == 3 {
+				return config.Param{}, fmt.Errorf("invalid --param '%s': a required parameter can't have a default", spec)
+			}
+		case "optional":
+			param.Required = false
+			if len(parts) == 3 {
+				param.Default = parts[2]
+			}
+		default:
+			return config.Param{}, fmt.Errorf("invalid --param '%s': expected name:required or name:optional[:default]", spec)
+		}
+	}
+
+	return param, nil
 }
 
 // promptAliasName asks the user for the alias name.
@@ -112,9 +329,9 @@ func promptAliasName() (string, error) {
 	prompt := promptui.Prompt{
 		Label: "Alias name",
 		Validate: func(input string) error {
-			// Check if name is valid format
-			if !namePattern.MatchString(input) {
-				return fmt.Errorf("name must start with a letter and contain only letters, numbers, and hyphens")
+			// Check if name follows the configured name policy
+			if err := alias.ValidateName(input); err != nil {
+				return err
 			}
 
 			// Check if alias already exists
@@ -154,6 +371,38 @@ func promptDescription() (string, error) {
 	return prompt.Run()
 }
 
+// promptWorkingDir asks for an optional working directory.
+func promptWorkingDir() (string, error) {
+	prompt := promptui.Prompt{
+		Label:   "Working directory (optional, e.g. ~/projects/app)",
+		Default: "",
+	}
+
+	dir, err := prompt.Run()
+	return strings.TrimSpace(dir), err
+}
+
+// promptTags asks for optional comma-separated tags.
+func promptTags() ([]string, error) {
+	prompt := promptui.Prompt{
+		Label:   "Tags (optional, comma-separated, e.g. git,work)",
+		Default: "",
+	}
+
+	input, err := prompt.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	for _, tag := range strings.Split(input, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
 // promptParams detects {{placeholders}} in the command and asks
 // the user to define each parameter.
 func promptParams(command string) ([]config.Param, error) {
@@ -174,7 +423,7 @@ func promptParams(command string) ([]config.Param, error) {
 	for _, name := range placeholders {
 		fmt.Printf("\nParameter: {{%s}}\n", name)
 
-		param, err := promptParamDetails(name)
+		param, err := promptParamDetails(config.Param{Name: name, Required: true})
 		if err != nil {
 			return nil, err
 		}
@@ -186,11 +435,13 @@ func promptParams(command string) ([]config.Param, error) {
 }
 
 // promptParamDetails asks for details about a single parameter.
-func promptParamDetails(name string) (config.Param, error) {
+// The existing param's values are offered as defaults, and fields
+// not asked about (like quoting) are kept as they are.
+func promptParamDetails(existing config.Param) (config.Param, error) {
 	// Get description
 	descPrompt := promptui.Prompt{
 		Label:   "Description",
-		Default: "",
+		Default: existing.Description,
 	}
 	description, err := descPrompt.Run()
 	if err != nil {
@@ -198,9 +449,14 @@ func promptParamDetails(name string) (config.Param, error) {
 	}
 
 	// Ask if required
+	cursor := 0
+	if !existing.Required {
+		cursor = 1
+	}
 	requiredPrompt := promptui.Select{
-		Label: "Is this parameter required?",
-		Items: []string{"Yes (must be provided)", "No (optional)"},
+		Label:     "Is this parameter required?",
+		Items:     []string{"Yes (must be provided)", "No (optional)"},
+		CursorPos: cursor,
 	}
 	requiredIdx, _, err := requiredPrompt.Run()
 	if err != nil {
@@ -208,25 +464,77 @@ func promptParamDetails(name string) (config.Param, error) {
 	}
 	required := requiredIdx == 0
 
-	// If optional, ask for default value
-	var defaultVal string
+	// Ask for the allowed values, if the param only takes a fixed set
+	choicesPrompt := promptui.Prompt{
+		Label:   "Allowed values, comma-separated (leave empty for any)",
+		Default: strings.Join(existing.Choices, ","),
+	}
+	choicesInput, err := choicesPrompt.Run()
+	if err != nil {
+		return config.Param{}, err
+	}
+	var choices []string
+	for _, choice := range strings.Split(choicesInput, ",") {
+		if choice = strings.TrimSpace(choice); choice != "" {
+			choices = append(choices, choice)
+		}
+	}
+
+	// Ask for a pattern values must match
+	validatePrompt := promptui.Prompt{
+		Label:   "Validation regex (leave empty for none)",
+		Default: existing.Validate,
+		Validate: func(input string) error {
+			if _, err := regexp.Compile(input); err != nil {
+				return fmt.Errorf("invalid regex: %v", err)
+			}
+			return nil
+		},
+	}
+	validate, err := validatePrompt.Run()
+	if err != nil {
+		return config.Param{}, err
+	}
+
+	param := existing
+	param.Description = description
+	param.Required = required
+	param.Choices = choices
+	param.Validate = validate
+	param.Default = ""
+
+	// If optional, ask for default value.
+	// It has to satisfy the constraints given above.
 	if !required {
 		defaultPrompt := promptui.Prompt{
 			Label:   "Default value (leave empty for none)",
-			Default: "",
+			Default: existing.Default,
+			Validate: func(input string) error {
+				if input == "" {
+					return nil
+				}
+				check := param
+				check.Default = input
+				return alias.ValidateConstraints(config.Alias{Params: []config.Param{check}})
+			},
 		}
-		defaultVal, err = defaultPrompt.Run()
+		param.Default, err = defaultPrompt.Run()
 		if err != nil {
 			return config.Param{}, err
 		}
 	}
 
-	return config.Param{
-		Name:        name,
-		Description: description,
-		Required:    required,
-		Default:     defaultVal,
-	}, nil
+	return param, nil
+}
+
+// printAliasJSON prints an alias as indented JSON to stdout.
+func printAliasJSON(a config.Alias) {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		printError(fmt.Sprintf("Failed to encode alias: %v", err))
+		os.Exit(1)
+	}
+	fmt.Println(string(data))
 }
 
 // handlePromptError handles errors from promptui.
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"aliasly/internal/alias"
+	"aliasly/internal/config"
+)
+
+// completionCmd represents the completion command.
+// It prints a shell completion script to stdout.
+var completionCmd = &cobra.Command{
+	Use:   "completion <bash|zsh|fish|powershell>",
+	Short: "Generate a shell completion script",
+	Long: `Generate a shell completion script for al.
+
+The script completes subcommands, flags, and the names of your own
+aliases. Alias names are looked up each time you press Tab, so new
+aliases are picked up without regenerating the script.
+
+Load completions for the current session:
+
+  Bash:        source <(al completion bash)
+  Zsh:         source <(al completion zsh)
+  Fish:        al completion fish | source
+  PowerShell:  al completion powershell | Out-String | Invoke-Expression
+
+To load them for every session, add the line to your shell config
+file (.bashrc, .zshrc, config.fish, or your PowerShell profile).`,
+
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+	Run:       runCompletionCmd,
+}
+
+func init() {
+	// Replace Cobra's default completion command with our own
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(completionCmd)
+}
+
+func runCompletionCmd(cmd *cobra.Command, args []string) {
+	if !isCompletionShell(args[0]) {
+		printError("Unsupported shell: " + args[0] + " (use bash, zsh, fish, or powershell)")
+		os.Exit(1)
+	}
+
+	if err := writeCompletion(os.Stdout, args[0]); err != nil {
+		printError("Failed to generate completion script: " + err.Error())
+		os.Exit(1)
+	}
+}
+
+// isCompletionShell reports whether completion scripts can be
+// generated for shell.
+func isCompletionShell(shell string) bool {
+	switch shell {
+	case "bash", "zsh", "fish", "powershell":
+		return true
+	}
+	return false
+}
+
+// writeCompletion writes the completion script for shell to w.
+// shell must be one for which isCompletionShell is true.
+func writeCompletion(w io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		return rootCmd.GenBashCompletionV2(w, true)
+	case "zsh":
+		return rootCmd.GenZshCompletion(w)
+	case "fish":
+		return rootCmd.GenFishCompletion(w, true)
+	default:
+		return rootCmd.GenPowerShellCompletionWithDesc(w)
+	}
+}
+
+// completeAliasNames suggests alias names for the first argument.
+// Descriptions are included so shells that support them can show
+// what each alias does.
+func completeAliasNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// Use the cached names so completion stays fast with large configs
+	aliases, err := config.GetAliasSummaries()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	names := make([]string, 0, len(aliases))
+	for _, a := range aliases {
+		if !strings.HasPrefix(a.Name, toComplete) {
+			continue
+		}
+		if a.Description != "" {
+			names = append(names, a.Name+"\t"+a.Description)
+		} else {
+			names = append(names, a.Name)
+		}
+	}
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeAliasArgs suggests alias names for the first argument and,
+// for the arguments after it, the allowed values of the positional
+// parameter at that position.
+func completeAliasArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeAliasNames(cmd, args, toComplete)
+	}
+
+	a, found := alias.Find(args[0])
+	if !found {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// Only choices can be completed; other params could be anything,
+	// so fall back to the shell's default (file) completion
+	index := len(args) - 1
+	if index >= len(a.Params) || len(a.Params[index].Choices) == 0 {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+
+	choices := make([]string, 0, len(a.Params[index].Choices))
+	for _, choice := range a.Params[index].Choices {
+		if strings.HasPrefix(choice, toComplete) {
+			choices = append(choices, choice)
+		}
+	}
+
+	return choices, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeAliasParams suggests alias names for the first argument and
+// that alias's parameter names for the second.
+func completeAliasParams(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeAliasNames(cmd, args, toComplete)
+	}
+	if len(args) > 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	a, found := alias.Find(args[0])
+	if !found {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names := make([]string, 0, len(a.Params))
+	for _, p := range a.Params {
+		if strings.HasPrefix(p.Name, toComplete) {
+			names = append(names, p.Name)
+		}
+	}
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -5,15 +5,19 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"os/exec"
 	"os/signal"
 	"runtime"
+	"strconv"
+	"strings"
 	"syscall"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
+	"aliasly/internal/config"
 	"aliasly/internal/webui"
 )
 
@@ -38,32 +42,146 @@ This starts a local web server that provides a visual interface for:
   - Editing existing aliases
   - Deleting aliases
 
-The server runs on localhost only and shuts down when you press Ctrl+C.
+The server runs on localhost on a random port and shuts down when you
+press Ctrl+C. Use --addr and --port to choose where it listens, e.g. to
+reach it from outside a container.
+
+API requests are protected by a random token that is generated on startup
+and included in the URL that is printed and opened. Use --token to choose
+the token yourself, e.g. for automation. Anyone with the token and network
+access to the server can edit your aliases, which run as commands on this
+machine, so only bind to other addresses on networks you trust.
+
+Running aliases from the UI is disabled unless you pass --allow-run.
+
+While the server runs, changes made to the config file outside the UI
+(e.g. in a text editor) are picked up automatically, so the UI never
+works from a stale copy. Pass --watch=false to turn this off.
+
+To call the API from a frontend served elsewhere, allow its origin with
+--cors-origin. Cross-origin requests are blocked by default; they still
+need the token.
+
+Use --path to print where the config file lives, or --reveal to also
+open its directory in your file manager.
+
+Use --dump to print the effective configuration as aliasly sees it,
+after loading and applying defaults. Unlike 'al export', which copies
+the config file, this shows what is actually in effect, including the
+aliases of a project config (.aliasly.yaml), which are listed at the top.
 
 Examples:
-  al config    # Open web configuration UI
-  al ui        # Short form`,
+  al config           # Open web configuration UI
+  al ui               # Short form
+  al config --path    # Print the config file location
+  al config --reveal  # Open the config directory
+  al config --dump    # Print the effective configuration
+  al config --addr 0.0.0.0 --port 8799  # Listen on all interfaces
+  al config --allow-run                  # Also allow running aliases
+  al config --cors-origin http://localhost:3000  # Allow another frontend`,
 
 	// Run function
 	Run: runConfigCmd,
 }
 
+// configPathFlag, when true, prints the config file location and exits
+var configPathFlag bool
+
+// configRevealFlag, when true, opens the config directory and exits
+var configRevealFlag bool
+
+// configDumpFlag, when true, prints the effective configuration and exits
+var configDumpFlag bool
+
+// configAddrFlag is the IP address the web UI listens on
+var configAddrFlag string
+
+// configPortFlag is the port the web UI listens on (0 = random free port)
+var configPortFlag int
+
+// configTokenFlag is the token API requests must send (random if empty)
+var configTokenFlag string
+
+// configAllowRunFlag, when true, lets the web UI run aliases
+var configAllowRunFlag bool
+
+// configCORSOriginFlag lists the origins allowed to call the API (CORS)
+var configCORSOriginFlag []string
+
+// configWatchFlag, when true, reloads the config when it changes on disk
+var configWatchFlag bool
+
+func init() {
+	configCmd.Flags().BoolVar(&configPathFlag, "path", false, "Print the config file location")
+	configCmd.Flags().BoolVar(&configRevealFlag, "reveal", false, "Open the config directory in your file manager")
+	configCmd.Flags().BoolVar(&configDumpFlag, "dump", false, "Print the effective configuration as YAML")
+	configCmd.Flags().StringVar(&configAddrFlag, "addr", "127.0.0.1", "IP address for the web UI to listen on")
+	configCmd.Flags().IntVar(&configPortFlag, "port", 0, "Port for the web UI to listen on (default: a random free port)")
+	configCmd.Flags().BoolVar(&configAllowRunFlag, "allow-run", false, "Allow running aliases from the web UI")
+	configCmd.Flags().StringSliceVar(&configCORSOriginFlag, "cors-origin", nil, "Origin allowed to call the API from another page (repeatable, or * for any)")
+	configCmd.Flags().BoolVar(&configWatchFlag, "watch", true, "Reload the config when the file is changed outside the UI")
+	configCmd.Flags().StringVar(&configTokenFlag, "token", "", "Token required for API requests (default: randomly generated)")
+}
+
 // runConfigCmd executes the config command.
 func runConfigCmd(cmd *cobra.Command, args []string) {
-	// Find an available port by listening on port 0
-	// The OS will assign an available port
-	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if configPathFlag || configRevealFlag {
+		runConfigLocation()
+		return
+	}
+
+	if configDumpFlag {
+		runConfigDump()
+		return
+	}
+
+	ip := net.ParseIP(configAddrFlag)
+	if ip == nil {
+		printError(fmt.Sprintf("Invalid address '%s' (expected an IP address like 127.0.0.1 or 0.0.0.0)", configAddrFlag))
+		os.Exit(1)
+	}
+	if configPortFlag < 0 || configPortFlag > 65535 {
+		printError(fmt.Sprintf("Invalid port %d (expected 1-65535, or 0 for a random port)", configPortFlag))
+		os.Exit(1)
+	}
+
+	// Listen on the requested port. With port 0 (the default),
+	// the OS assigns an available port
+	listenAddr := net.JoinHostPort(ip.String(), strconv.Itoa(configPortFlag))
+	listener, err := net.Listen("tcp", listenAddr)
 	if err != nil {
-		printError(fmt.Sprintf("Failed to find available port: %v", err))
+		printError(fmt.Sprintf("Failed to listen on %s: %v", listenAddr, err))
 		os.Exit(1)
 	}
 
 	// Get the port that was assigned
 	port := listener.Addr().(*net.TCPAddr).Port
-	url := fmt.Sprintf("http://127.0.0.1:%d", port)
+
+	// The browser can't open 0.0.0.0 or ::, so use loopback for those
+	urlHost := ip.String()
+	if ip.IsUnspecified() {
+		urlHost = "127.0.0.1"
+	}
+	url := fmt.Sprintf("http://%s", net.JoinHostPort(urlHost, strconv.Itoa(port)))
+
+	// Protect the API with a token, so other local users or web pages
+	// can't change aliases through the server
+	token := configTokenFlag
+	if token == "" {
+		token, err = webui.GenerateToken()
+		if err != nil {
+			printError(err.Error())
+			os.Exit(1)
+		}
+	}
+	url += "/?token=" + neturl.QueryEscape(token)
 
 	// Create the HTTP server with our handlers
-	server := webui.NewServer()
+	server := webui.NewServer(webui.Options{
+		Token:       token,
+		AllowRun:    configAllowRunFlag,
+		CORSOrigins: configCORSOriginFlag,
+	})
 	httpServer := &http.Server{
 		Handler: server.Handler(),
 	}
@@ -85,8 +203,18 @@ func runConfigCmd(cmd *cobra.Command, args []string) {
 	fmt.Printf("Server running at: %s\n", url)
 	fmt.Println()
 
+	// Anyone who can reach the UI can change the commands aliasly runs
+	if !ip.IsLoopback() {
+		red := color.New(color.FgRed, color.Bold)
+		red.Fprintf(os.Stderr, "Warning: Listening on %s, which is reachable from other machines.\n", net.JoinHostPort(ip.String(), strconv.Itoa(port)))
+		red.Println("The UI is protected by the token in the URL above, but anyone with the")
+		red.Println("token who can reach it can edit your aliases, which run as commands")
+		red.Println("on this machine.")
+		fmt.Println()
+	}
+
 	// Try to open the browser
-	if err := openBrowser(url); err != nil {
+	if err := openWithOS(url); err != nil {
 		// If browser can't be opened, just show the URL
 		fmt.Printf("Could not open browser automatically.\n")
 		fmt.Printf("Please open this URL in your browser: %s\n", url)
@@ -94,6 +222,26 @@ func runConfigCmd(cmd *cobra.Command, args []string) {
 		fmt.Println("Opening in your default browser...")
 	}
 
+	// Pick up edits made outside the UI, so it doesn't serve stale data.
+	// The server still works without it, e.g. if the OS has run out of
+	// file watches.
+	if configWatchFlag {
+		stopWatching, err := config.WatchConfig(func(err error) {
+			if err != nil {
+				yellow := color.New(color.FgYellow)
+				yellow.Printf("Config changed on disk but couldn't be loaded, keeping the previous version: %v\n", err)
+				return
+			}
+			fmt.Println("Config changed on disk, reloaded.")
+		})
+		if err != nil {
+			yellow := color.New(color.FgYellow)
+			yellow.Fprintf(os.Stderr, "Warning: %v; changes made outside the UI won't be picked up\n", err)
+		} else {
+			defer stopWatching()
+		}
+	}
+
 	fmt.Println()
 	fmt.Println("Press Ctrl+C to stop the server")
 
@@ -116,31 +264,91 @@ func runConfigCmd(cmd *cobra.Command, args []string) {
 	green.Println("Server stopped.")
 }
 
-// openBrowser opens the specified URL in the default browser.
+// runConfigLocation prints the config file location and, with --reveal,
+// opens the config directory in the OS file manager.
+func runConfigLocation() {
+	fmt.Println(config.GetConfigFilePath())
+
+	if !configRevealFlag {
+		return
+	}
+
+	configDir := config.GetConfigDir()
+	if err := openWithOS(configDir); err != nil {
+		printError(fmt.Sprintf("Could not open %s: %v", configDir, err))
+		os.Exit(1)
+	}
+}
+
+// openWithOS opens a URL or file path with the OS default handler,
+// e.g. the default browser for URLs or the file manager for directories.
 // It handles different operating systems appropriately.
-func openBrowser(url string) error {
-	var cmd string
-	var args []string
+func openWithOS(target string) error {
+	cmd, args, err := openCommand(target)
+	if err != nil {
+		return err
+	}
+
+	// Start the command but don't wait for it to finish
+	// (the browser or file manager will keep running after we return)
+	return exec.Command(cmd, args...).Start()
+}
 
-	// Different operating systems have different commands to open URLs
+// openCommand returns the program and arguments that open target with
+// its default application on this operating system.
+func openCommand(target string) (string, []string, error) {
+	// Different operating systems have different commands to open things
 	switch runtime.GOOS {
 	case "darwin":
-		// macOS uses the "open" command
-		cmd = "open"
-		args = []string{url}
+		// macOS uses the "open" command (Finder for directories)
+		return "open", []string{target}, nil
 	case "linux":
 		// Linux uses xdg-open (part of xdg-utils package)
-		cmd = "xdg-open"
-		args = []string{url}
+		return "xdg-open", []string{target}, nil
 	case "windows":
-		// Windows uses "start" command through cmd
-		cmd = "cmd"
-		args = []string{"/c", "start", url}
+		// Windows uses "start" command through cmd (Explorer for directories)
+		return "cmd", []string{"/c", "start", target}, nil
 	default:
-		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+		return "", nil, fmt.Errorf("unsupported platform: %s", runtime.GOOS)
 	}
+}
 
-	// Start the command but don't wait for it to finish
-	// (the browser will keep running after we return)
-	return exec.Command(cmd, args...).Start()
+// runConfigDump prints the in-memory configuration in the config
+// file's format (YAML or TOML).
+// This is the config after loading, which may differ from the file
+// (for example, starter aliases when there was no file yet).
+func runConfigDump() {
+	cfg, err := config.Get()
+	if err != nil {
+		printError(fmt.Sprintf("Failed to load config: %v", err))
+		os.Exit(1)
+	}
+
+	// Show the aliases as list and run see them, with the project
+	// config's merged in
+	effective := *cfg
+	effective.Aliases, err = config.GetAllAliases()
+	if err != nil {
+		printError(fmt.Sprintf("Failed to load config: %v", err))
+		os.Exit(1)
+	}
+
+	var projectNames []string
+	for _, a := range effective.Aliases {
+		if config.IsProjectAlias(a.Name) {
+			projectNames = append(projectNames, a.Name)
+		}
+	}
+
+	data, err := config.Marshal(&effective, config.FileFormat())
+	if err != nil {
+		printError(fmt.Sprintf("Failed to encode config: %v", err))
+		os.Exit(1)
+	}
+
+	fmt.Printf("# Effective configuration (from %s)\n", config.GetConfigFilePath())
+	if len(projectNames) > 0 {
+		fmt.Printf("# Aliases from %s: %s\n", config.ProjectConfigPath(), strings.Join(projectNames, ", "))
+	}
+	fmt.Print(string(data))
 }
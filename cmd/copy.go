@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"aliasly/internal/alias"
+)
+
+// copyCmd represents the copy command.
+// It duplicates an existing alias under a new name.
+var copyCmd = &cobra.Command{
+	Use:     "copy <source> <destination>",
+	Aliases: []string{"cp"},
+	Short:   "Duplicate an alias under a new name",
+	Long: `Duplicate an alias under a new name.
+
+The command, description, parameters and other settings are copied,
+so you can tweak the copy (e.g. with 'al edit') without retyping it.
+Usage stats are not copied.
+
+Examples:
+  al copy gc gcm    # Create 'gcm' as a copy of 'gc'
+  al cp gp gpf      # Short form`,
+
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeAliasNames,
+	Run:               runCopyCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(copyCmd)
+}
+
+func runCopyCmd(cmd *cobra.Command, args []string) {
+	srcName, destName := args[0], args[1]
+
+	src, found := alias.Find(srcName)
+	if !found {
+		printError(fmt.Sprintf("Alias '%s' not found", srcName))
+		os.Exit(1)
+	}
+
+	if err := alias.ValidateName(destName); err != nil {
+		printError(err.Error())
+		os.Exit(1)
+	}
+
+	if _, exists := alias.Find(destName); exists {
+		printError(fmt.Sprintf("Alias '%s' already exists", destName))
+		os.Exit(1)
+	}
+
+	// Usage stats are kept by name, so the copy starts from zero
+	dest := src.Clone()
+	dest.Name = destName
+
+	if err := alias.Add(dest); err != nil {
+		printError(fmt.Sprintf("Failed to save alias: %v", err))
+		os.Exit(1)
+	}
+
+	if !quietFlag {
+		green := color.New(color.FgGreen, color.Bold)
+		green.Printf("Copied alias '%s' to '%s'\n", srcName, destName)
+	}
+	fmt.Printf("Usage: al %s\n", alias.BuildUsageString(dest))
+}
@@ -0,0 +1,331 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"aliasly/internal/config"
+)
+
+// doctorCmd represents the doctor command.
+// It checks the aliasly setup for common problems.
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check your aliasly setup for problems",
+	Long: `Check your aliasly setup for common problems.
+
+Each check is reported as ok, warning, or failed, with a hint on how
+to fix it. The command exits non-zero if any check failed.
+
+Checks:
+  - The config directory exists and is writable, and the config file
+    can be parsed.
+  - The config directory and config.yaml aren't writable by other users.
+    Aliases are commands you run, so anyone who can edit them can run
+    commands as you. Use --fix to restrict them to 0700 and 0600.
+  - The shell aliases run with can be found.
+  - The state directory (stats, history, backups) is writable.
+  - The command 'al config' uses to open the browser is installed.
+  - Shell integration ('al init') is set up in your shell's config file.
+
+Problems that stop aliases from running fail; the others are warnings.
+
+Examples:
+  al doctor          # Run all checks
+  al doctor --fix    # Also fix problems that can be fixed automatically`,
+
+	Args: cobra.NoArgs,
+	Run:  runDoctorCmd,
+}
+
+// doctorFixFlag, when true, fixes problems that can be fixed automatically
+var doctorFixFlag bool
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().BoolVar(&doctorFixFlag, "fix", false, "Fix problems that can be fixed automatically")
+}
+
+// checkStatus is the outcome of a doctor check.
+type checkStatus int
+
+const (
+	checkOK checkStatus = iota
+	checkWarn
+	checkFail
+)
+
+// checkResult is the outcome of a single doctor check.
+type checkResult struct {
+	// status is whether the check passed
+	status checkStatus
+
+	// message describes what was checked and found
+	message string
+
+	// hint explains how to fix a problem (empty if there's none)
+	hint string
+}
+
+func runDoctorCmd(cmd *cobra.Command, args []string) {
+	results := []checkResult{checkConfigDir(), checkConfigFile()}
+	results = append(results, checkConfigPermissions(doctorFixFlag)...)
+	results = append(results, checkShell(), checkStateDir(), checkBrowser(), checkShellIntegration())
+
+	failed := false
+	for _, r := range results {
+		printCheckResult(r)
+		if r.status == checkFail {
+			failed = true
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// printCheckResult prints a check result with a colored status marker.
+func printCheckResult(r checkResult) {
+	switch r.status {
+	case checkOK:
+		color.New(color.FgGreen, color.Bold).Print("  ok    ")
+	case checkWarn:
+		color.New(color.FgYellow, color.Bold).Print("  warn  ")
+	case checkFail:
+		color.New(color.FgRed, color.Bold).Print("  fail  ")
+	}
+	fmt.Println(r.message)
+
+	if r.hint != "" {
+		color.New(color.Faint).Printf("        %s\n", r.hint)
+	}
+}
+
+// checkConfigPermissions checks that the config directory and file
+// can't be modified by other users. If fix is true, the permissions
+// are tightened to 0700 for the directory and 0600 for the file.
+// Problems are only warnings, since aliasly still works.
+func checkConfigPermissions(fix bool) []checkResult {
+	// Windows permissions aren't expressed as mode bits
+	if runtime.GOOS == "windows" {
+		return []checkResult{{status: checkOK, message: "Config permissions (not checked on Windows)"}}
+	}
+
+	paths := []struct {
+		path string
+		mode os.FileMode
+	}{
+		{config.GetConfigDir(), 0700},
+		{config.GetConfigFilePath(), 0600},
+	}
+
+	results := make([]checkResult, 0, len(paths))
+	for _, p := range paths {
+		info, err := os.Stat(p.path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			results = append(results, checkResult{
+				status:  checkWarn,
+				message: fmt.Sprintf("Could not check permissions of %s: %v", p.path, err),
+			})
+			continue
+		}
+
+		perm := info.Mode().Perm()
+
+		// Only group or world write access is a risk
+		if perm&0022 == 0 {
+			results = append(results, checkResult{
+				status:  checkOK,
+				message: fmt.Sprintf("%s is only writable by you (%04o)", p.path, perm),
+			})
+			continue
+		}
+
+		if fix {
+			if err := os.Chmod(p.path, p.mode); err != nil {
+				results = append(results, checkResult{
+					status:  checkWarn,
+					message: fmt.Sprintf("Could not fix permissions of %s: %v", p.path, err),
+					hint:    fmt.Sprintf("Run: chmod %o %s", p.mode, p.path),
+				})
+				continue
+			}
+			results = append(results, checkResult{
+				status:  checkOK,
+				message: fmt.Sprintf("Fixed permissions of %s (%04o -> %04o)", p.path, perm, p.mode),
+			})
+			continue
+		}
+
+		results = append(results, checkResult{
+			status:  checkWarn,
+			message: fmt.Sprintf("%s is writable by other users (%04o)", p.path, perm),
+			hint:    fmt.Sprintf("Run 'al doctor --fix' or: chmod %o %s", p.mode, p.path),
+		})
+	}
+
+	return results
+}
+
+// checkConfigDir checks that the config directory exists and is writable.
+func checkConfigDir() checkResult {
+	dir := config.GetConfigDir()
+
+	if err := checkWritableDir(dir); err != nil {
+		return checkResult{
+			status:  checkFail,
+			message: fmt.Sprintf("Config directory %s", err),
+			hint:    "Fix its permissions, or set ALIASLY_CONFIG_DIR to a writable location",
+		}
+	}
+
+	return checkResult{status: checkOK, message: fmt.Sprintf("Config directory %s is writable", dir)}
+}
+
+// checkConfigFile checks that the config file can be parsed.
+// A missing file is only a warning, since it's created on first use.
+func checkConfigFile() checkResult {
+	path := config.GetConfigFilePath()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return checkResult{
+			status:  checkWarn,
+			message: fmt.Sprintf("Config file %s doesn't exist yet", path),
+			hint:    "It is created with a few starter aliases the next time aliasly runs",
+		}
+	}
+	if err != nil {
+		return checkResult{
+			status:  checkFail,
+			message: fmt.Sprintf("Could not read config file %s: %v", path, err),
+			hint:    "Fix the file's permissions",
+		}
+	}
+
+	cfg, err := config.ParseFormat(data, config.FormatFromPath(path))
+	if err != nil {
+		return checkResult{
+			status:  checkFail,
+			message: fmt.Sprintf("Could not parse config file %s: %v", path, err),
+			hint:    "Fix it with a text editor, or roll back with 'al restore'",
+		}
+	}
+
+	return checkResult{status: checkOK, message: fmt.Sprintf("Config file %s has %d alias(es)", path, len(cfg.Aliases))}
+}
+
+// checkShell checks that the shell aliases run with can be found.
+func checkShell() checkResult {
+	shell := config.GetDefaultShell()
+	source := "$SHELL"
+	if cfg, err := config.Get(); err == nil && cfg.Settings.Shell != "" {
+		shell = cfg.Settings.Shell
+		source = "settings.shell"
+	}
+
+	path, err := exec.LookPath(shell)
+	if err != nil {
+		return checkResult{
+			status:  checkFail,
+			message: fmt.Sprintf("Shell %s (from %s) not found", shell, source),
+			hint:    "Set 'shell' under settings to an installed shell, e.g. /bin/sh",
+		}
+	}
+
+	return checkResult{status: checkOK, message: fmt.Sprintf("Shell %s (from %s) found", path, source)}
+}
+
+// checkStateDir checks that the state directory is writable. Without
+// it stats, history and backups are lost, but aliases still run.
+func checkStateDir() checkResult {
+	dir := config.GetStateDir()
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return checkResult{status: checkOK, message: fmt.Sprintf("State directory %s will be created when needed", dir)}
+	}
+	if err := checkWritableDir(dir); err != nil {
+		return checkResult{
+			status:  checkWarn,
+			message: fmt.Sprintf("State directory %s", err),
+			hint:    "Fix its permissions, or set ALIASLY_STATE_DIR to a writable location",
+		}
+	}
+
+	return checkResult{status: checkOK, message: fmt.Sprintf("State directory %s is writable", dir)}
+}
+
+// checkBrowser checks that the command 'al config' uses to open the
+// web UI is installed.
+func checkBrowser() checkResult {
+	opener, _, err := openCommand("")
+	if err == nil {
+		_, err = exec.LookPath(opener)
+	}
+	if err != nil {
+		return checkResult{
+			status:  checkWarn,
+			message: "'al config' can't open the browser: " + err.Error(),
+			hint:    "Install it (on Linux, the xdg-utils package), or open the URL 'al config' prints yourself",
+		}
+	}
+
+	return checkResult{status: checkOK, message: fmt.Sprintf("'al config' opens the browser with %s", opener)}
+}
+
+// checkShellIntegration checks that the shell's config file runs
+// 'al init', so aliases can be used without the al prefix.
+func checkShellIntegration() checkResult {
+	rcFile := GetShellConfigFile()
+	line := `eval "$(al init)"`
+	if strings.HasSuffix(rcFile, "config.fish") {
+		line = "al init | source"
+	}
+
+	data, err := os.ReadFile(rcFile)
+	if err != nil || !strings.Contains(string(data), "al init") {
+		return checkResult{
+			status:  checkWarn,
+			message: fmt.Sprintf("Shell integration is not set up in %s", rcFile),
+			hint:    fmt.Sprintf("Add '%s' to it to use aliases without 'al' and get tab completion", line),
+		}
+	}
+
+	return checkResult{status: checkOK, message: fmt.Sprintf("Shell integration is set up in %s", rcFile)}
+}
+
+// checkWritableDir returns an error if dir doesn't exist, isn't a
+// directory, or a file can't be created in it. The error reads as the
+// end of a sentence starting with the directory's name.
+func checkWritableDir(dir string) error {
+	info, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%s doesn't exist", dir)
+	}
+	if err != nil {
+		return fmt.Errorf("%s can't be accessed: %v", dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	// Permission bits don't tell the whole story (read-only mounts,
+	// ACLs), so try to actually create a file
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return fmt.Errorf("%s is not writable", dir)
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return nil
+}
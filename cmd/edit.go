@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+
+	"aliasly/internal/alias"
+	"aliasly/internal/config"
+)
+
+// editCmd represents the edit command.
+// It opens a single alias as YAML in the user's editor.
+var editCmd = &cobra.Command{
+	Use:   "edit <alias>",
+	Short: "Edit an alias in your $EDITOR",
+	Long: `Edit an alias in your text editor.
+
+The alias is written to a temporary YAML file and opened in $VISUAL or
+$EDITOR (falling back to vi, nano, or notepad). When you save and close
+the editor, the alias is validated and updated. If the YAML is invalid
+or the command has undefined placeholders, the old alias is kept.
+
+Examples:
+  al edit gc                # Edit the 'gc' alias
+  EDITOR=nano al edit gc    # Use a specific editor`,
+
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAliasNames,
+	Run:               runEditCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+}
+
+func runEditCmd(cmd *cobra.Command, args []string) {
+	aliasName := args[0]
+
+	a, found := alias.Find(aliasName)
+	if !found {
+		printError(fmt.Sprintf("Alias '%s' not found", aliasName))
+		os.Exit(1)
+	}
+	exitIfProjectAlias(aliasName)
+
+	original, err := yaml.Marshal(a)
+	if err != nil {
+		printError(fmt.Sprintf("Failed to encode alias: %v", err))
+		os.Exit(1)
+	}
+
+	// Open the editor and wait for it to exit.
+	// A non-zero exit (e.g. the user pressed Ctrl+C or quit with :cq)
+	// is treated as a cancellation.
+	edited, err := editInTempFile(original, aliasName)
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			if !quietFlag {
+				fmt.Println("Cancelled. Alias was not changed.")
+			}
+			return
+		}
+		printError(err.Error())
+		os.Exit(1)
+	}
+
+	if bytes.Equal(edited, original) {
+		fmt.Println("No changes made.")
+		return
+	}
+
+	updated, err := parseEditedAlias(edited, aliasName)
+	if err != nil {
+		printError(err.Error())
+		fmt.Println("The alias was not changed.")
+		os.Exit(1)
+	}
+
+	if err := alias.Update(updated); err != nil {
+		printError(fmt.Sprintf("Failed to save alias: %v", err))
+		os.Exit(1)
+	}
+
+	if !quietFlag {
+		green := color.New(color.FgGreen, color.Bold)
+		green.Printf("Alias '%s' updated successfully!\n", aliasName)
+		fmt.Printf("Usage: al %s\n", alias.BuildUsageString(updated))
+	}
+	warnUnusedParams(updated)
+}
+
+// editInTempFile writes data to a temporary YAML file, opens it in the
+// user's editor, and returns the edited contents.
+// The temporary file is always removed before returning.
+func editInTempFile(data []byte, name string) ([]byte, error) {
+	tmpFile, err := os.CreateTemp("", "aliasly-"+name+"-*.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return nil, fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	tmpFile.Close()
+
+	if err := openEditor(tmpPath); err != nil {
+		return nil, err
+	}
+
+	edited, err := os.ReadFile(tmpPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read edited file: %w", err)
+	}
+
+	return edited, nil
+}
+
+// parseEditedAlias parses and validates the YAML written by the editor.
+// The alias name can't be changed through edit.
+func parseEditedAlias(data []byte, name string) (config.Alias, error) {
+	var a config.Alias
+	if err := yaml.Unmarshal(data, &a); err != nil {
+		return config.Alias{}, fmt.Errorf("invalid YAML format: %w", err)
+	}
+
+	if a.Name != name {
+		return config.Alias{}, fmt.Errorf("the alias name can't be changed (expected '%s', got '%s')", name, a.Name)
+	}
+
+	if strings.TrimSpace(a.Command) == "" {
+		return config.Alias{}, fmt.Errorf("command cannot be empty")
+	}
+
+	if undefined := alias.ValidatePlaceholders(a); len(undefined) > 0 {
+		return config.Alias{}, fmt.Errorf("command has undefined placeholders: %s", strings.Join(undefined, ", "))
+	}
+
+	if err := alias.ValidateVariadic(a); err != nil {
+		return config.Alias{}, err
+	}
+
+	if err := alias.ValidateConstraints(a); err != nil {
+		return config.Alias{}, err
+	}
+
+	return a, nil
+}
+
+// openEditor opens path in the user's editor and waits for it to exit.
+// It uses $VISUAL or $EDITOR, falling back to a default for the OS.
+func openEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = defaultEditor()
+	}
+	if editor == "" {
+		return fmt.Errorf("no editor found; set the EDITOR environment variable")
+	}
+
+	// $EDITOR may include arguments (e.g. "code --wait"),
+	// so run it through the shell like other commands
+	var editorCmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		editorCmd = exec.Command("cmd", "/C", editor+` "`+path+`"`)
+	} else {
+		shell := config.GetDefaultShell()
+		editorCmd = exec.Command(shell, "-c", editor+" "+alias.QuoteForShell(shell, path))
+	}
+
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+
+	// Let the editor handle Ctrl+C itself instead of killing aliasly,
+	// so the temporary file is always cleaned up
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	return editorCmd.Run()
+}
+
+// defaultEditor returns the first available fallback editor.
+func defaultEditor() string {
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+
+	for _, candidate := range []string{"vi", "nano"} {
+		if _, err := exec.LookPath(candidate); err == nil {
+			return candidate
+		}
+	}
+
+	return ""
+}
@@ -1,11 +1,17 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"aliasly/internal/alias"
 	"aliasly/internal/config"
 )
 
@@ -14,46 +20,323 @@ import (
 var exportCmd = &cobra.Command{
 	Use:   "export [file]",
 	Short: "Export aliases to a file",
-	Long: `Export your aliases configuration to a YAML file for backup.
+	Long: `Export your aliases configuration to a file for backup.
 
 If no file is specified, the config is printed to stdout.
+The format is yaml, toml, json or sh. Without --format, it is taken from
+the output file's extension, or else matches your config file.
+All formats except sh can be re-imported with 'al import'.
+
+The sh format prints plain shell aliases and functions, to seed a
+machine's shell without installing aliasly. Aliases without parameters
+become 'alias name=...'; aliases with parameters become functions that
+take them as positional arguments. The output is POSIX shell, so it works
+with sh and dash as well as bash and zsh. Aliases using features a plain shell
+can't express (env, working_dir, [[ ]] segments, {{alias:...}} references
+or shebang scripts) are skipped with a comment.
+
+When writing to a file, the file is replaced atomically, so an
+interrupted export never leaves it half-written. An existing non-empty
+file is only overwritten with --force.
+
+Use --header to add a comment block noting the source machine, aliasly
+version and export date. Import ignores these comments.
 
 Examples:
-  al export                    # Print config to terminal
-  al export backup.yaml        # Save to backup.yaml
-  al export ~/my-aliases.yaml  # Save to home directory`,
+  al export                      # Print config to terminal
+  al export backup.yaml          # Save to backup.yaml
+  al export -o backup.yaml       # Same, with a flag
+  al export --force backup.yaml  # Overwrite an existing backup.yaml
+  al export ~/my-aliases.yaml    # Save to home directory
+  al export backup.json          # Save as JSON
+  al export -f toml              # Print config as TOML
+  al export -f sh >> ~/.bashrc   # Add the aliases to your shell
+  al export --header team.yaml   # Include a metadata header for sharing`,
 
 	Args: cobra.MaximumNArgs(1),
 	Run:  runExportCmd,
 }
 
+// exportFormatFlag selects the output format (yaml, toml, json or sh)
+var exportFormatFlag string
+
+// exportHeaderFlag, when true, prepends a metadata comment block
+var exportHeaderFlag bool
+
+// exportOutputFlag is the file to write to, as an alternative to the argument
+var exportOutputFlag string
+
+// exportForceFlag, when true, allows overwriting an existing non-empty file
+var exportForceFlag bool
+
 func init() {
 	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVarP(&exportFormatFlag, "format", "f", "", "Output format: yaml, toml, json or sh (default: from file extension or config)")
+	exportCmd.Flags().BoolVar(&exportHeaderFlag, "header", false, "Prepend a metadata comment block (not for JSON)")
+	exportCmd.Flags().StringVarP(&exportOutputFlag, "output", "o", "", "File to write to (default: stdout)")
+	exportCmd.Flags().BoolVar(&exportForceFlag, "force", false, "Overwrite the output file if it already has content")
 }
 
 func runExportCmd(cmd *cobra.Command, args []string) {
-	// Get config file path
-	configPath := config.GetConfigFilePath()
+	if exportOutputFlag != "" {
+		if len(args) > 0 {
+			printError("Give the output file either as an argument or with --output, not both")
+			os.Exit(1)
+		}
+		args = []string{exportOutputFlag}
+	}
+
+	format := exportFormatFlag
+	if format == "" {
+		format = exportFormatFor(args)
+	}
 
-	// Read the config file
-	data, err := os.ReadFile(configPath)
+	data, err := exportData(format)
 	if err != nil {
-		printError(fmt.Sprintf("Failed to read config: %v", err))
+		printError(err.Error())
 		os.Exit(1)
 	}
 
+	if exportHeaderFlag {
+		// JSON has no comment syntax, so the header can't be added there
+		if format == "json" {
+			printError("--header isn't supported for JSON exports")
+			os.Exit(1)
+		}
+		data = append(exportHeader(), data...)
+	}
+
 	// If no output file specified, print to stdout
 	if len(args) == 0 {
 		fmt.Print(string(data))
 		return
 	}
 
-	// Write to the specified file
+	// Refuse to clobber an existing file by mistake
 	outputPath := args[0]
-	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+	if info, err := os.Stat(outputPath); err == nil && !exportForceFlag {
+		if info.IsDir() {
+			printError(fmt.Sprintf("%s is a directory", outputPath))
+			os.Exit(1)
+		}
+		if info.Size() > 0 {
+			printError(fmt.Sprintf("%s already exists; pass --force to overwrite it", outputPath))
+			os.Exit(1)
+		}
+	}
+
+	// Write to a temp file and rename it into place, so the file is
+	// never left half-written
+	if err := config.WriteFileAtomic(outputPath, data, 0644); err != nil {
 		printError(fmt.Sprintf("Failed to write to %s: %v", outputPath, err))
 		os.Exit(1)
 	}
 
 	fmt.Printf("Config exported to: %s\n", outputPath)
 }
+
+// exportFormatFor picks the export format when --format isn't given:
+// from the output file's extension if there is one, otherwise the
+// format of the config file.
+func exportFormatFor(args []string) string {
+	if len(args) > 0 {
+		switch strings.ToLower(filepath.Ext(args[0])) {
+		case ".json":
+			return "json"
+		case ".toml":
+			return config.FormatTOML
+		case ".sh", ".bash", ".zsh":
+			return "sh"
+		case ".yaml", ".yml":
+			return config.FormatYAML
+		}
+	}
+	return config.FileFormat()
+}
+
+// exportData returns the config encoded in the given format.
+// When the format matches the config file, the file is copied as-is
+// (keeping any comments); otherwise it is marshaled from the loaded config.
+func exportData(format string) ([]byte, error) {
+	switch format {
+	case "yaml", "yml", "toml":
+		if format == "yml" {
+			format = config.FormatYAML
+		}
+
+		// Read the config file if it's already in the right format
+		if format == config.FileFormat() {
+			data, err := os.ReadFile(config.GetConfigFilePath())
+			if err != nil {
+				return nil, fmt.Errorf("failed to read config: %w", err)
+			}
+			return data, nil
+		}
+
+		cfg, err := config.Get()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+
+		data, err := config.Marshal(cfg, format)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode config: %w", err)
+		}
+		return data, nil
+
+	case "json":
+		cfg, err := config.Get()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+
+		data, err := json.MarshalIndent(cfg, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode config: %w", err)
+		}
+		return append(data, '\n'), nil
+
+	case "sh":
+		aliases, err := alias.GetAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load aliases: %w", err)
+		}
+		return []byte(exportShell(aliases)), nil
+
+	default:
+		return nil, fmt.Errorf("unknown format '%s' (expected yaml, toml, json or sh)", format)
+	}
+}
+
+// exportHeader builds the comment block prepended by --header.
+// YAML, TOML and shell scripts all use # comments, so it works for each.
+// Comments aren't part of the Config struct, so they are simply
+// prepended to the file contents.
+func exportHeader() []byte {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	header := "# Exported by aliasly\n"
+	header += fmt.Sprintf("# Source machine: %s\n", hostname)
+	header += fmt.Sprintf("# Aliasly version: %s\n", Version)
+	header += fmt.Sprintf("# Export date: %s\n", time.Now().Format(time.RFC3339))
+	header += "\n"
+
+	return []byte(header)
+}
+
+// exportShell returns the aliases as shell code: an alias line for each
+// alias without parameters and a function for each alias with them.
+// Aliases that can't be expressed in plain shell are skipped with a comment.
+func exportShell(aliases []alias.Alias) string {
+	var b strings.Builder
+	b.WriteString("# Aliases exported from aliasly\n")
+
+	for _, a := range aliases {
+		if reason := shellExportBlocker(a); reason != "" {
+			fmt.Fprintf(&b, "\n# Skipped '%s': %s\n", a.Name, reason)
+			continue
+		}
+
+		command := strings.TrimRight(strings.ReplaceAll(a.Command, "\r\n", "\n"), "\n")
+		if a.Description != "" {
+			fmt.Fprintf(&b, "\n# %s\n", a.Description)
+		} else {
+			b.WriteString("\n")
+		}
+
+		// Aliases can't take arguments in the middle of a command or
+		// span lines, so those become functions
+		if len(a.Params) == 0 && !strings.Contains(command, "\n") {
+			fmt.Fprintf(&b, "alias %s=%s\n", a.Name, alias.QuotePOSIX(command))
+			continue
+		}
+
+		b.WriteString(shellFunction(a, command))
+	}
+
+	return b.String()
+}
+
+// shellExportBlocker returns why an alias can't be exported as plain
+// shell code, or an empty string if it can.
+func shellExportBlocker(a alias.Alias) string {
+	switch {
+	case strings.HasPrefix(a.Command, "#!"):
+		return "it is a script for another interpreter"
+	case strings.Contains(a.Command, "{{alias:"):
+		return "it references other aliases"
+	case alias.HasOptionalSegments(a):
+		return "it has optional [[ ]] segments"
+	case len(a.Env) > 0:
+		return "it sets environment variables"
+	case a.WorkingDir != "":
+		return "it has a working directory"
+	}
+	return ""
+}
+
+// shellFunction returns a POSIX shell function that runs command with
+// each {{param}} placeholder replaced by the matching positional
+// parameter, in the order the params are declared. Quoted params are
+// wrapped in double quotes.
+//
+// Defaults and a variadic param that isn't the only one need the
+// arguments in variables first, so those functions copy them into
+// _name variables and shift the rest into "$@" for the variadic param.
+// Their body is a subshell, so the variables don't leak into the
+// caller's shell, like aliasly running the command in its own process.
+func shellFunction(a alias.Alias, command string) string {
+	needsVars := false
+	for i, p := range a.Params {
+		if p.Default != "" || (p.Variadic && i > 0) {
+			needsVars = true
+		}
+	}
+
+	if !needsVars {
+		for i, p := range a.Params {
+			value := "$" + strconv.Itoa(i+1)
+			switch {
+			case p.Variadic:
+				value = "$@"
+			case i >= 9:
+				value = "${" + strconv.Itoa(i+1) + "}"
+			}
+			command = replaceShellParam(command, p, value)
+		}
+		// The body isn't indented, since that would break here-docs
+		return fmt.Sprintf("%s() {\n%s\n}\n", a.Name, command)
+	}
+
+	var prelude strings.Builder
+	for i, p := range a.Params {
+		if p.Variadic {
+			// shift fails if there are fewer arguments than that
+			fmt.Fprintf(&prelude, "if [ $# -gt %d ]; then shift %d; else set --; fi\n", i, i)
+			command = replaceShellParam(command, p, "$@")
+			continue
+		}
+
+		n := strconv.Itoa(i + 1)
+		if p.Default != "" {
+			fmt.Fprintf(&prelude, "_%s=${%s:-%s}\n", p.Name, n, alias.QuotePOSIX(p.Default))
+		} else {
+			fmt.Fprintf(&prelude, "_%s=${%s}\n", p.Name, n)
+		}
+		command = replaceShellParam(command, p, "$_"+p.Name)
+	}
+
+	return fmt.Sprintf("%s() (\n%s%s\n)\n", a.Name, prelude.String(), command)
+}
+
+// replaceShellParam replaces the param's placeholder in command with
+// value, in double quotes if the param is quoted.
+func replaceShellParam(command string, p alias.Param, value string) string {
+	if p.Quote {
+		value = `"` + value + `"`
+	}
+	return strings.ReplaceAll(command, "{{"+p.Name+"}}", value)
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"aliasly/internal/config"
+)
+
+// historyCmd represents the history command.
+// It shows the commands aliasly has run, most recent last.
+var historyCmd = &cobra.Command{
+	Use:   "history [alias]",
+	Short: "Show the commands aliases have run",
+	Long: `Show the expanded commands aliasly has run, with when they ran and
+their exit codes, so you can see exactly what a parameterized alias did.
+
+History is off by default, since commands can contain private values.
+Turn it on with 'history: true' under settings; 'history_size' sets how
+many entries are kept (default 1000). It is stored in history.jsonl in
+aliasly's state directory (~/.local/state/aliasly by default).
+
+Examples:
+  al history               # Show the last 20 commands
+  al history -n 100        # Show the last 100 commands
+  al history deploy        # Only show runs of the 'deploy' alias
+  al history --host laptop # Only show runs on the machine 'laptop'
+  al history --clear       # Delete the history`,
+
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeAliasNames,
+	Run:               runHistoryCmd,
+}
+
+// historyLimitFlag is how many entries to show (0 = all)
+var historyLimitFlag int
+
+// historyJSONFlag, when true, prints the entries as JSON
+var historyJSONFlag bool
+
+// historyClearFlag, when true, deletes the history
+var historyClearFlag bool
+
+// historyHostFlag, if set, only shows runs on the machine with this name
+var historyHostFlag string
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.Flags().IntVarP(&historyLimitFlag, "limit", "n", 20, "Number of entries to show (0 for all)")
+	historyCmd.Flags().BoolVar(&historyJSONFlag, "json", false, "Print the entries as JSON")
+	historyCmd.Flags().BoolVar(&historyClearFlag, "clear", false, "Delete the history")
+	historyCmd.Flags().StringVar(&historyHostFlag, "host", "", "Only show runs on the machine with this hostname")
+}
+
+func runHistoryCmd(cmd *cobra.Command, args []string) {
+	if historyClearFlag {
+		if err := config.ClearHistory(); err != nil {
+			printError(err.Error())
+			os.Exit(1)
+		}
+		if !quietFlag {
+			green := color.New(color.FgGreen, color.Bold)
+			green.Println("History cleared")
+		}
+		return
+	}
+
+	entries, err := config.LoadHistory()
+	if err != nil {
+		printError(err.Error())
+		os.Exit(1)
+	}
+
+	if len(args) == 1 || historyHostFlag != "" {
+		var matching []config.HistoryEntry
+		for _, e := range entries {
+			if len(args) == 1 && e.Alias != args[0] {
+				continue
+			}
+			if historyHostFlag != "" && e.Host != historyHostFlag {
+				continue
+			}
+			matching = append(matching, e)
+		}
+		entries = matching
+	}
+
+	if historyLimitFlag > 0 && len(entries) > historyLimitFlag {
+		entries = entries[len(entries)-historyLimitFlag:]
+	}
+
+	if historyJSONFlag {
+		if entries == nil {
+			entries = []config.HistoryEntry{}
+		}
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			printError(err.Error())
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	if cfg, err := config.Get(); err == nil && !cfg.Settings.History {
+		fmt.Println("History is turned off. Set 'history: true' under settings to record commands.")
+		fmt.Println()
+	}
+
+	if len(entries) == 0 {
+		if len(args) == 1 || historyHostFlag != "" {
+			fmt.Println("No matching commands recorded.")
+		} else {
+			fmt.Println("No commands recorded yet.")
+		}
+		return
+	}
+
+	nameColor := color.New(color.FgCyan, color.Bold)
+	dimColor := color.New(color.Faint)
+	red := color.New(color.FgRed)
+
+	for _, e := range entries {
+		dimColor.Printf("%s  ", e.Time.Local().Format("2006-01-02 15:04:05"))
+		if e.Host != "" {
+			dimColor.Printf("%s  ", e.Host)
+		}
+		nameColor.Print(e.Alias)
+		if e.ExitCode == 0 {
+			dimColor.Println("  exit 0")
+		} else {
+			red.Printf("  exit %d\n", e.ExitCode)
+		}
+
+		// Indent continuation lines of multi-line commands
+		command := strings.ReplaceAll(strings.TrimRight(e.Command, "\n"), "\n", "\n    ")
+		fmt.Printf("  $ %s\n", command)
+	}
+}
@@ -3,31 +3,43 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
-	"go.yaml.in/yaml/v3"
 
+	"aliasly/internal/alias"
 	"aliasly/internal/config"
 )
 
 // importCmd represents the import command.
 // It imports configuration from a file.
 var importCmd = &cobra.Command{
-	Use:   "import <file>",
-	Short: "Import aliases from a file",
-	Long: `Import aliases from a YAML configuration file.
+	Use:   "import <file|directory>",
+	Short: "Import aliases from a file or directory",
+	Long: `Import aliases from a YAML, TOML or JSON configuration file.
+
+If a directory is given, every *.yaml, *.yml, *.toml and *.json file inside it
+is read and their aliases are combined. Files that fail to parse are
+skipped with a warning.
 
 By default, this merges new aliases with your existing ones.
 Existing aliases with the same name will be skipped.
 
+Use --force to let the imported aliases win instead: existing aliases
+with the same name are overwritten, and all others are kept.
 Use --replace to completely replace your config instead.
 
 Examples:
   al import backup.yaml           # Merge aliases from backup.yaml
   al import ~/my-aliases.yaml     # Merge from home directory
-  al import backup.yaml --replace # Replace entire config`,
+  al import backup.yaml --replace # Replace entire config
+  al import backup.yaml --force   # Merge, overwriting existing aliases
+  al import aliases.toml          # Merge from a TOML file
+  al import ~/aliases.d/          # Merge every alias file in a directory`,
 
 	Args: cobra.ExactArgs(1),
 	Run:  runImportCmd,
@@ -36,32 +48,51 @@ Examples:
 // replaceFlag determines whether to replace instead of merge
 var replaceFlag bool
 
+// importForceFlag, when true, overwrites existing aliases when merging
+var importForceFlag bool
+
 func init() {
 	rootCmd.AddCommand(importCmd)
 	importCmd.Flags().BoolVarP(&replaceFlag, "replace", "r", false, "Replace entire config instead of merging")
+	importCmd.Flags().BoolVar(&importForceFlag, "force", false, "Overwrite existing aliases with the imported ones when merging")
 }
 
 func runImportCmd(cmd *cobra.Command, args []string) {
 	inputPath := args[0]
 
 	// Check if input file exists
-	if _, err := os.Stat(inputPath); os.IsNotExist(err) {
+	info, err := os.Stat(inputPath)
+	if os.IsNotExist(err) {
 		printError(fmt.Sprintf("File not found: %s", inputPath))
 		os.Exit(1)
 	}
 
-	// Read the input file
-	data, err := os.ReadFile(inputPath)
-	if err != nil {
-		printError(fmt.Sprintf("Failed to read file: %v", err))
-		os.Exit(1)
-	}
-
-	// Validate YAML structure
 	var newConfig config.Config
-	if err := yaml.Unmarshal(data, &newConfig); err != nil {
-		printError(fmt.Sprintf("Invalid YAML format: %v", err))
-		os.Exit(1)
+
+	if err == nil && info.IsDir() {
+		// Directory mode - combine the aliases from every file inside
+		combined, err := loadImportDir(inputPath)
+		if err != nil {
+			printError(err.Error())
+			os.Exit(1)
+		}
+		newConfig = *combined
+	} else {
+		// Read the input file
+		data, err := os.ReadFile(inputPath)
+		if err != nil {
+			printError(fmt.Sprintf("Failed to read file: %v", err))
+			os.Exit(1)
+		}
+
+		// Validate the file is actually an aliasly config
+		format := config.FormatFromPath(inputPath)
+		parsed, err := config.ParseFormat(data, format)
+		if err != nil {
+			printError(err.Error())
+			os.Exit(1)
+		}
+		newConfig = *parsed
 	}
 
 	// Show what will be imported
@@ -70,20 +101,84 @@ func runImportCmd(cmd *cobra.Command, args []string) {
 
 	if replaceFlag {
 		// Replace mode - ask for confirmation
-		if err := replaceConfig(inputPath, data); err != nil {
+		if err := replaceConfig(&newConfig); err != nil {
 			printError(err.Error())
 			os.Exit(1)
 		}
 	} else {
 		// Merge mode (default)
-		if err := mergeConfig(&newConfig); err != nil {
+		if err := mergeConfig(&newConfig, importForceFlag); err != nil {
 			printError(err.Error())
 			os.Exit(1)
 		}
 	}
 }
 
-func replaceConfig(inputPath string, data []byte) error {
+// loadImportDir reads every *.yaml, *.yml, *.toml and *.json file in dir and
+// combines their aliases into a single config.
+// Files that can't be read or parsed are skipped with a warning.
+// Settings are taken from the current config so a replace keeps them.
+func loadImportDir(dir string) (*config.Config, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	combined := &config.Config{Version: 1}
+	if cfg, err := config.Get(); err == nil {
+		combined.Version = cfg.Version
+		combined.Settings = cfg.Settings
+	}
+
+	yellow := color.New(color.FgYellow)
+	files := 0
+
+	// os.ReadDir returns entries sorted by filename, so the order is stable
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if ext != ".yaml" && ext != ".yml" && ext != ".toml" && ext != ".json" {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			yellow.Fprintf(os.Stderr, "Warning: Skipping %s: %v\n", entry.Name(), err)
+			continue
+		}
+
+		// YAML is a superset of JSON, so one parser handles both
+		fileConfig, err := config.ParseFormat(data, config.FormatFromPath(path))
+		if err != nil {
+			yellow.Fprintf(os.Stderr, "Warning: Skipping %s: %v\n", entry.Name(), err)
+			continue
+		}
+
+		names := make([]string, 0, len(fileConfig.Aliases))
+		for _, a := range fileConfig.Aliases {
+			names = append(names, a.Name)
+		}
+		fmt.Printf("  %s: %d alias(es) %v\n", entry.Name(), len(fileConfig.Aliases), names)
+
+		combined.Aliases = append(combined.Aliases, fileConfig.Aliases...)
+		files++
+	}
+
+	if files == 0 {
+		return nil, fmt.Errorf("no valid alias files (*.yaml, *.yml, *.toml, *.json) found in %s", dir)
+	}
+	fmt.Println()
+
+	return combined, nil
+}
+
+// replaceConfig replaces the whole config with newConfig, after asking.
+// It is saved in the config file's own format, whatever the import's was.
+func replaceConfig(newConfig *config.Config) error {
 	// Ask if user wants to backup current config
 	backupPrompt := promptui.Select{
 		Label: "Do you want to backup your current config first?",
@@ -93,22 +188,20 @@ func replaceConfig(inputPath string, data []byte) error {
 	backupIdx, _, err := backupPrompt.Run()
 	if err != nil {
 		if err == promptui.ErrInterrupt {
-			fmt.Println("Cancelled.")
+			if !quietFlag {
+				fmt.Println("Cancelled.")
+			}
 			return nil
 		}
 		return err
 	}
 
 	if backupIdx == 0 {
-		// Create backup
-		configPath := config.GetConfigFilePath()
-		backupPath := configPath + ".backup"
-
-		currentData, err := os.ReadFile(configPath)
-		if err == nil {
-			if err := os.WriteFile(backupPath, currentData, 0644); err != nil {
-				return fmt.Errorf("failed to create backup: %w", err)
-			}
+		backupPath, err := config.Backup()
+		if err != nil {
+			return err
+		}
+		if backupPath != "" {
 			fmt.Printf("Backup saved to: %s\n", backupPath)
 		}
 	}
@@ -122,35 +215,39 @@ func replaceConfig(inputPath string, data []byte) error {
 	confirmIdx, _, err := confirmPrompt.Run()
 	if err != nil {
 		if err == promptui.ErrInterrupt {
-			fmt.Println("Cancelled.")
+			if !quietFlag {
+				fmt.Println("Cancelled.")
+			}
 			return nil
 		}
 		return err
 	}
 
 	if confirmIdx == 0 {
-		fmt.Println("Cancelled.")
+		if !quietFlag {
+			fmt.Println("Cancelled.")
+		}
 		return nil
 	}
 
-	// Write the new config
-	configPath := config.GetConfigFilePath()
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
+	// Save the new config like any other change, so the previous one
+	// is kept as a backup and a failed write can't truncate the file
+	if err := config.Replace(newConfig); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
 
-	// Reload config
-	if err := config.Load(); err != nil {
-		return fmt.Errorf("failed to reload config: %w", err)
+	if !quietFlag {
+		green := color.New(color.FgGreen, color.Bold)
+		green.Println("Config replaced successfully!")
 	}
 
-	green := color.New(color.FgGreen, color.Bold)
-	green.Println("Config replaced successfully!")
-
 	return nil
 }
 
-func mergeConfig(newConfig *config.Config) error {
+// mergeConfig adds the aliases in newConfig that don't exist yet.
+// If overwrite is true, existing aliases with the same name are
+// replaced by the imported ones rather than skipped.
+func mergeConfig(newConfig *config.Config, overwrite bool) error {
 	// Get current aliases
 	currentAliases, err := config.GetAllAliases()
 	if err != nil {
@@ -158,68 +255,118 @@ func mergeConfig(newConfig *config.Config) error {
 	}
 
 	// Build map of existing aliases
-	existing := make(map[string]bool)
+	existing := make(map[string]config.Alias)
 	for _, a := range currentAliases {
-		existing[a.Name] = true
+		existing[a.Name] = a
 	}
 
-	// Count new and duplicate aliases
+	// Count new, changed and unchanged aliases
 	newCount := 0
-	duplicates := []string{}
+	changed := []string{}
+	unchanged := []string{}
 
 	for _, a := range newConfig.Aliases {
-		if existing[a.Name] {
-			duplicates = append(duplicates, a.Name)
-		} else {
+		current, exists := existing[a.Name]
+		switch {
+		case !exists:
 			newCount++
+		case reflect.DeepEqual(current, a):
+			unchanged = append(unchanged, a.Name)
+		default:
+			changed = append(changed, a.Name)
 		}
 	}
 
 	fmt.Printf("New aliases to add: %d\n", newCount)
-	if len(duplicates) > 0 {
-		fmt.Printf("Already exist (will skip): %v\n", duplicates)
+	if len(changed) > 0 {
+		if overwrite {
+			fmt.Printf("Already exist (will overwrite): %v\n", changed)
+		} else {
+			fmt.Printf("Already exist (will skip): %v\n", changed)
+		}
+	}
+	if len(unchanged) > 0 {
+		fmt.Printf("Already exist, unchanged: %v\n", unchanged)
 	}
 	fmt.Println()
 
-	if newCount == 0 {
+	updateCount := 0
+	if overwrite {
+		updateCount = len(changed)
+	}
+
+	if newCount == 0 && updateCount == 0 {
 		fmt.Println("No new aliases to import. All aliases already exist.")
 		return nil
 	}
 
 	// Confirm
+	label := fmt.Sprintf("Add %d new alias(es)?", newCount)
+	if updateCount > 0 {
+		label = fmt.Sprintf("Add %d new and overwrite %d existing alias(es)?", newCount, updateCount)
+	}
 	confirmPrompt := promptui.Select{
-		Label: fmt.Sprintf("Add %d new alias(es)?", newCount),
-		Items: []string{"No, cancel", "Yes, add them"},
+		Label: label,
+		Items: []string{"No, cancel", "Yes, import them"},
 	}
 
 	confirmIdx, _, err := confirmPrompt.Run()
 	if err != nil {
 		if err == promptui.ErrInterrupt {
-			fmt.Println("Cancelled.")
+			if !quietFlag {
+				fmt.Println("Cancelled.")
+			}
 			return nil
 		}
 		return err
 	}
 
 	if confirmIdx == 0 {
-		fmt.Println("Cancelled.")
+		if !quietFlag {
+			fmt.Println("Cancelled.")
+		}
 		return nil
 	}
 
-	// Add new aliases
-	added := 0
+	// Add new aliases, and overwrite changed ones if asked to
+	// Aliases are saved like any other edit, so broken ones (e.g. with
+	// undefined placeholders) are rejected rather than failing at run time
+	added, updated := 0, 0
+	var skipped []string
 	for _, a := range newConfig.Aliases {
-		if !existing[a.Name] {
-			if err := config.AddAlias(a); err != nil {
-				fmt.Printf("Warning: Failed to add '%s': %v\n", a.Name, err)
+		current, exists := existing[a.Name]
+		switch {
+		case !exists:
+			if err := alias.Add(a); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Failed to add '%s': %v\n", a.Name, err)
+				skipped = append(skipped, a.Name)
 			} else {
 				added++
 			}
+		case overwrite && !reflect.DeepEqual(current, a):
+			if err := alias.Update(a); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Failed to update '%s': %v\n", a.Name, err)
+				skipped = append(skipped, a.Name)
+			} else {
+				updated++
+			}
 		}
 	}
 
+	if len(skipped) > 0 {
+		yellow := color.New(color.FgYellow)
+		yellow.Fprintf(os.Stderr, "Skipped %d alias(es) that couldn't be saved: %s\n", len(skipped), strings.Join(skipped, ", "))
+	}
+
+	if quietFlag {
+		return nil
+	}
 	green := color.New(color.FgGreen, color.Bold)
-	green.Printf("Added %d new alias(es)!\n", added)
+	if overwrite {
+		green.Printf("Added %d, updated %d, unchanged %d alias(es)!\n", added, updated, len(unchanged))
+	} else {
+		green.Printf("Added %d new alias(es)!\n", added)
+	}
 
 	return nil
 }
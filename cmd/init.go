@@ -14,14 +14,24 @@ import (
 // initCmd represents the init command.
 // It outputs shell code that creates aliases for all configured aliases.
 var initCmd = &cobra.Command{
-	Use:   "init",
+	Use:   "init [bash|zsh|fish]",
 	Short: "Output shell integration code",
-	Long: `Output shell code that creates aliases for all your configured aliases.
+	Long: `Output shell code that creates aliases for all your configured aliases
+and sets up tab completion for al.
 
 Add this to your shell config file (.bashrc, .zshrc, etc.):
 
+  # Aliasly - command alias manager
   eval "$(al init)"
 
+or for fish (config.fish):
+
+  # Aliasly - command alias manager
+  al init | source
+
+The shell is detected from $SHELL; pass bash, zsh or fish to choose it.
+'al uninstall' removes these lines again.
+
 After that, you can use your aliases directly without the 'al' prefix:
 
   gs              # instead of: al gs
@@ -34,7 +44,9 @@ The 'al' command is still used for management:
   al config       # Open web UI
   al list         # List aliases`,
 
-	Run: runInitCmd,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	Run:       runInitCmd,
 }
 
 func init() {
@@ -55,8 +67,15 @@ func runInitCmd(cmd *cobra.Command, args []string) {
 		alPath = "al" // Fallback to assuming it's in PATH
 	}
 
-	// Detect shell type
+	// Use the given shell, or detect it
 	shell := os.Getenv("SHELL")
+	if len(args) == 1 {
+		shell = args[0]
+		if shell != "bash" && shell != "zsh" && shell != "fish" {
+			printError("Unsupported shell: " + shell + " (use bash, zsh, or fish)")
+			os.Exit(ExitUsage)
+		}
+	}
 	isZsh := contains(shell, "zsh")
 	isFish := contains(shell, "fish")
 
@@ -85,6 +104,26 @@ func runInitCmd(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	// Tab completion for al itself
+	fmt.Println()
+	fmt.Println("# Completion for al")
+	var completionErr error
+	switch {
+	case isFish:
+		completionErr = writeCompletion(os.Stdout, "fish")
+	case isZsh:
+		// compdef only exists once the completion system is loaded
+		// (compinit), so skip completion rather than fail without it
+		fmt.Println("if (( $+functions[compdef] )); then")
+		completionErr = writeCompletion(os.Stdout, "zsh")
+		fmt.Println("fi")
+	default:
+		completionErr = writeCompletion(os.Stdout, "bash")
+	}
+	if completionErr != nil {
+		fmt.Fprintf(os.Stderr, "# Error generating completion: %v\n", completionErr)
+	}
+
 	fmt.Println()
 	fmt.Println("# Aliasly integration loaded")
 }
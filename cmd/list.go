@@ -1,14 +1,18 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
 	"aliasly/internal/alias"
+	"aliasly/internal/config"
 )
 
 // listCmd represents the list command.
@@ -30,14 +34,76 @@ var listCmd = &cobra.Command{
 Shows the alias name, the command it runs, and a description.
 Parameters are shown in the command with {{name}} syntax.
 
+Use --tag to show only aliases whose tags match an expression.
+Expressions combine tag names with and, or, not and parentheses.
+
+Aliases are listed in the order they were added. Use --sort to order
+them by name, by command, or with the most recently run first.
+
+Use --group-by-tag to list aliases under a header for each tag, with
+untagged aliases under "ungrouped". An alias with several tags is
+listed under each of them.
+
 Examples:
-  al list    # Show all aliases
-  al ls      # Short form`,
+  al list                                # Show all aliases
+  al ls                                  # Short form
+  al list --tag git                      # Aliases tagged 'git'
+  al list --tag "git and not deprecated" # Tag expression
+  al list --tag "docker or k8s"
+  al list --sort name                    # Alphabetical order
+  al list --sort recent                  # Most recently run first
+  al list --group-by-tag                 # Group aliases under their tags
+  al list --porcelain                    # Stable tab-separated output
+  al list --pager                        # Page long output through $PAGER
+  al list --usage-example                # Show a ready-to-copy invocation
+  al list --json | jq '.[].name'         # JSON output for scripts
+  al list -q                             # Just the names, one per line
+
+Porcelain output prints one alias per line with these tab-separated
+fields, in this order (stable across versions):
+  name, command, description, tags (comma-separated)
+Tabs, newlines and backslashes inside fields are escaped as \t, \n, \\.`,
 
 	// Run is the function to execute
 	Run: runListCmd,
 }
 
+// listTagFlag is a tag expression used to filter the listed aliases
+var listTagFlag string
+
+// listPorcelainFlag, when true, prints stable tab-separated output
+var listPorcelainFlag bool
+
+// listPagerFlag, when true, pipes the output through a pager
+var listPagerFlag bool
+
+// listUsageExampleFlag, when true, shows an example invocation per alias
+var listUsageExampleFlag bool
+
+// listJSONFlag, when true, prints the aliases as a JSON array
+var listJSONFlag bool
+
+// listNamesOnlyFlag, when true, prints only the alias names, one per line
+var listNamesOnlyFlag bool
+
+// listGroupByTagFlag, when true, lists aliases under a header per tag
+var listGroupByTagFlag bool
+
+// listSortFlag selects the order aliases are listed in
+// (empty for insertion order, or name, command or recent)
+var listSortFlag string
+
+func init() {
+	listCmd.Flags().StringVarP(&listTagFlag, "tag", "t", "", "Only show aliases matching a tag expression")
+	listCmd.Flags().BoolVar(&listPorcelainFlag, "porcelain", false, "Print stable, script-friendly tab-separated output")
+	listCmd.Flags().BoolVar(&listPagerFlag, "pager", false, "Pipe output through $PAGER (default: less -R)")
+	listCmd.Flags().BoolVar(&listJSONFlag, "json", false, "Print aliases as a JSON array")
+	listCmd.Flags().BoolVarP(&listNamesOnlyFlag, "names-only", "q", false, "Print only the alias names, one per line")
+	listCmd.Flags().BoolVar(&listUsageExampleFlag, "usage-example", false, "Show a ready-to-copy invocation with example values")
+	listCmd.Flags().BoolVar(&listGroupByTagFlag, "group-by-tag", false, "Group aliases under a header for each tag")
+	listCmd.Flags().StringVar(&listSortFlag, "sort", "", "Sort by name, command or recent (default: insertion order)")
+}
+
 // runListCmd executes the list command.
 func runListCmd(cmd *cobra.Command, args []string) {
 	// Get all aliases from config
@@ -47,6 +113,60 @@ func runListCmd(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	// Filter by tag expression if one was given
+	if listTagFlag != "" {
+		expr, err := alias.ParseTagExpr(listTagFlag)
+		if err != nil {
+			printError(err.Error())
+			os.Exit(1)
+		}
+
+		aliases = filterByTags(aliases, expr)
+		if len(aliases) == 0 && !listPorcelainFlag && !listJSONFlag && !listNamesOnlyFlag {
+			fmt.Printf("No aliases match tag expression: %s\n", listTagFlag)
+			return
+		}
+	}
+
+	// Sort if requested; the stored order is left untouched
+	if listSortFlag != "" {
+		aliases, err = sortAliases(aliases, listSortFlag)
+		if err != nil {
+			printError(err.Error())
+			os.Exit(1)
+		}
+	}
+
+	// Grouping only applies to the human-readable output
+	if listGroupByTagFlag && (listJSONFlag || listPorcelainFlag || listNamesOnlyFlag) {
+		printError("--group-by-tag can't be combined with --json, --porcelain or --names-only")
+		os.Exit(1)
+	}
+	if listNamesOnlyFlag && (listJSONFlag || listPorcelainFlag) {
+		printError("--names-only can't be combined with --json or --porcelain")
+		os.Exit(1)
+	}
+
+	// Names-only output is the bare names, so shell loops can read it
+	if listNamesOnlyFlag {
+		for _, a := range aliases {
+			fmt.Println(a.Name)
+		}
+		return
+	}
+
+	// JSON output is the aliases alone, with no headers or hints
+	if listJSONFlag {
+		printAliasesJSON(aliases)
+		return
+	}
+
+	// Porcelain output has no headers or hints, just one line per alias
+	if listPorcelainFlag {
+		printPorcelain(aliases)
+		return
+	}
+
 	// Check if there are any aliases
 	if len(aliases) == 0 {
 		fmt.Println("No aliases configured yet.")
@@ -56,39 +176,63 @@ func runListCmd(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	// Print a header
-	fmt.Printf("Found %d alias(es):\n\n", len(aliases))
+	// Page the output if requested and stdout is a terminal
+	w, closePager := startPager(listPagerFlag)
+	defer closePager()
 
-	// Print each alias
-	for _, a := range aliases {
-		printAlias(a)
+	// Group headers are decoration, and without them an alias with
+	// several tags would just repeat, so --quiet prints the flat list
+	if listGroupByTagFlag && !quietFlag {
+		printGroupedByTag(w, aliases)
+	} else {
+		// Print a header
+		if !quietFlag {
+			fmt.Fprintf(w, "Found %d alias(es):\n", len(aliases))
+			if path := config.ProjectConfigPath(); path != "" {
+				color.New(color.Faint).Fprintf(w, "Including project aliases from %s\n", path)
+			}
+			fmt.Fprintln(w)
+		}
+
+		// Print each alias
+		for _, a := range aliases {
+			printAlias(w, a)
+		}
 	}
 
 	// Print help footer
-	fmt.Println()
-	fmt.Println("Run 'al <alias>' to execute an alias")
-	fmt.Println("Run 'al add' to create a new alias")
-	fmt.Println("Run 'al remove <alias>' to delete an alias")
+	if quietFlag {
+		return
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'al <alias>' to execute an alias")
+	fmt.Fprintln(w, "Run 'al add' to create a new alias")
+	fmt.Fprintln(w, "Run 'al remove <alias>' to delete an alias")
 }
 
-// printAlias prints a single alias in a nice format.
-func printAlias(a alias.Alias) {
+// printAlias prints a single alias in a nice format to w.
+func printAlias(w io.Writer, a alias.Alias) {
 	// Create colored output
 	nameColor := color.New(color.FgCyan, color.Bold)
 	cmdColor := color.New(color.FgGreen)
 	dimColor := color.New(color.Faint)
 
 	// Print alias name (bold cyan)
-	nameColor.Printf("  %s", a.Name)
+	nameColor.Fprintf(w, "  %s", a.Name)
 
 	// Print description if present (dim)
 	if a.Description != "" {
-		dimColor.Printf(" - %s", a.Description)
+		dimColor.Fprintf(w, " - %s", a.Description)
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// Print the command (green)
-	cmdColor.Printf("    $ %s\n", a.Command)
+	cmdColor.Fprintf(w, "    $ %s\n", a.Command)
+
+	// Print working directory if set
+	if a.WorkingDir != "" {
+		dimColor.Fprintf(w, "    dir:    %s\n", a.WorkingDir)
+	}
 
 	// Print parameters if any
 	if len(a.Params) > 0 {
@@ -96,6 +240,9 @@ func printAlias(a alias.Alias) {
 		paramStrs := make([]string, 0, len(a.Params))
 		for _, p := range a.Params {
 			paramStr := p.Name
+			if p.Variadic {
+				paramStr += "..." // Ellipsis indicates it takes all remaining args
+			}
 			if p.Required {
 				paramStr += "*" // Asterisk indicates required
 			}
@@ -105,12 +252,152 @@ func printAlias(a alias.Alias) {
 			paramStrs = append(paramStrs, paramStr)
 		}
 
-		dimColor.Printf("    params: %s\n", strings.Join(paramStrs, ", "))
+		dimColor.Fprintf(w, "    params: %s\n", strings.Join(paramStrs, ", "))
+	}
+
+	// Print tags if any
+	if len(a.Tags) > 0 {
+		dimColor.Fprintf(w, "    tags:   %s\n", strings.Join(a.Tags, ", "))
 	}
 
 	// Print usage example
 	usageStr := alias.BuildUsageString(a)
-	dimColor.Printf("    usage:  al %s\n", usageStr)
+	dimColor.Fprintf(w, "    usage:  al %s\n", usageStr)
+
+	// Print a concrete invocation filled with defaults if requested
+	if listUsageExampleFlag {
+		dimColor.Fprintf(w, "    e.g.:   al %s\n", alias.BuildExampleInvocation(a))
+	}
+
+	fmt.Fprintln(w) // Empty line between aliases
+}
+
+// ungroupedHeader is the group name used for aliases without tags.
+const ungroupedHeader = "ungrouped"
 
-	fmt.Println() // Empty line between aliases
+// printGroupedByTag prints aliases under a header for each tag, sorted
+// by tag name, followed by the untagged aliases. Aliases keep their
+// order within a group.
+func printGroupedByTag(w io.Writer, aliases []alias.Alias) {
+	groups := make(map[string][]alias.Alias)
+	var ungrouped []alias.Alias
+	for _, a := range aliases {
+		if len(a.Tags) == 0 {
+			ungrouped = append(ungrouped, a)
+			continue
+		}
+		for _, tag := range a.Tags {
+			groups[tag] = append(groups[tag], a)
+		}
+	}
+
+	tags := make([]string, 0, len(groups))
+	for tag := range groups {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	// The summary line shows how many aliases are in each group
+	counts := make([]string, 0, len(tags)+1)
+	for _, tag := range tags {
+		counts = append(counts, fmt.Sprintf("%s %d", tag, len(groups[tag])))
+	}
+	if len(ungrouped) > 0 {
+		counts = append(counts, fmt.Sprintf("%s %d", ungroupedHeader, len(ungrouped)))
+	}
+	fmt.Fprintf(w, "Found %d alias(es) (%s):\n\n", len(aliases), strings.Join(counts, ", "))
+
+	headerColor := color.New(color.FgYellow, color.Bold)
+	printGroup := func(name string, members []alias.Alias) {
+		headerColor.Fprintf(w, "[%s]\n", name)
+		for _, a := range members {
+			printAlias(w, a)
+		}
+	}
+
+	for _, tag := range tags {
+		printGroup(tag, groups[tag])
+	}
+	if len(ungrouped) > 0 {
+		printGroup(ungroupedHeader, ungrouped)
+	}
+}
+
+// filterByTags returns the aliases whose tags match expr.
+func filterByTags(aliases []alias.Alias, expr *alias.TagExpr) []alias.Alias {
+	matched := make([]alias.Alias, 0, len(aliases))
+	for _, a := range aliases {
+		if expr.Match(a.Tags) {
+			matched = append(matched, a)
+		}
+	}
+	return matched
+}
+
+// sortAliases returns a sorted copy of aliases.
+// by is "name", "command" or "recent" (most recently run first; aliases
+// that have never run keep their order at the end).
+func sortAliases(aliases []alias.Alias, by string) ([]alias.Alias, error) {
+	sorted := make([]alias.Alias, len(aliases))
+	copy(sorted, aliases)
+
+	switch by {
+	case "name":
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].Name < sorted[j].Name
+		})
+	case "command":
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].Command < sorted[j].Command
+		})
+	case "recent":
+		stats, err := config.LoadStats()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load usage stats: %w", err)
+		}
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return stats[sorted[i].Name].LastUsed.After(stats[sorted[j].Name].LastUsed)
+		})
+	default:
+		return nil, fmt.Errorf("unknown sort order '%s' (expected name, command or recent)", by)
+	}
+
+	return sorted, nil
+}
+
+// printAliasesJSON prints aliases as an indented JSON array.
+// The fields follow the JSON tags of config.Alias.
+func printAliasesJSON(aliases []alias.Alias) {
+	// Print [] rather than null when there are no aliases
+	if aliases == nil {
+		aliases = []alias.Alias{}
+	}
+
+	// Don't escape characters like & and < that are common in commands
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(aliases); err != nil {
+		printError(fmt.Sprintf("Failed to encode aliases: %v", err))
+		os.Exit(1)
+	}
+}
+
+// porcelainEscaper escapes characters that would break the
+// tab-separated porcelain format.
+var porcelainEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// printPorcelain prints aliases in the stable porcelain format:
+// name<TAB>command<TAB>description<TAB>tags
+// The field order is a contract for scripts and must not change;
+// new fields may only be appended.
+func printPorcelain(aliases []alias.Alias) {
+	for _, a := range aliases {
+		fmt.Printf("%s\t%s\t%s\t%s\n",
+			porcelainEscaper.Replace(a.Name),
+			porcelainEscaper.Replace(a.Command),
+			porcelainEscaper.Replace(a.Description),
+			porcelainEscaper.Replace(strings.Join(a.Tags, ",")),
+		)
+	}
 }
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"aliasly/internal/alias"
+	"aliasly/internal/config"
+)
+
+// migrateParamsCmd represents the migrate-params command.
+// It walks through an alias's parameters to fill in missing details.
+var migrateParamsCmd = &cobra.Command{
+	Use:   "migrate-params <alias>",
+	Short: "Interactively fill in parameter details for an alias",
+	Long: `Interactively fill in parameter details for an existing alias.
+
+Aliases created with bare parameters (no description, required flag, or
+default) can be upgraded with this command. You are asked about each
+parameter in turn, with the current values offered as defaults, and can
+restrict it to a set of allowed values or a validation regex.
+
+Placeholders in the command that have no parameter definition yet are
+added too.
+
+Examples:
+  al migrate-params deploy`,
+
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAliasNames,
+	Run:               runMigrateParamsCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(migrateParamsCmd)
+}
+
+func runMigrateParamsCmd(cmd *cobra.Command, args []string) {
+	aliasName := args[0]
+
+	a, found := alias.Find(aliasName)
+	if !found {
+		printError(fmt.Sprintf("Alias '%s' not found", aliasName))
+		os.Exit(1)
+	}
+	exitIfProjectAlias(aliasName)
+
+	// Start with the defined params, then add any placeholders
+	// that were never defined
+	params := make([]config.Param, len(a.Params))
+	copy(params, a.Params)
+	for _, name := range alias.ValidatePlaceholders(a) {
+		params = append(params, config.Param{Name: name, Required: true})
+	}
+
+	if len(params) == 0 {
+		fmt.Printf("Alias '%s' has no parameters to migrate.\n", aliasName)
+		return
+	}
+
+	fmt.Printf("Alias: %s\n", a.Name)
+	fmt.Printf("Command: %s\n", a.Command)
+	fmt.Println()
+	fmt.Printf("Reviewing %d parameter(s):\n", len(params))
+
+	for i, p := range params {
+		fmt.Printf("\nParameter: {{%s}}\n", p.Name)
+
+		updated, err := promptParamDetails(p)
+		if err != nil {
+			handlePromptError(err)
+			return
+		}
+		params[i] = updated
+	}
+
+	a.Params = params
+
+	if err := alias.ValidateVariadic(a); err != nil {
+		printError(err.Error())
+		os.Exit(1)
+	}
+
+	if err := alias.ValidateConstraints(a); err != nil {
+		printError(err.Error())
+		os.Exit(1)
+	}
+
+	if err := alias.Update(a); err != nil {
+		printError(fmt.Sprintf("Failed to save alias: %v", err))
+		os.Exit(1)
+	}
+
+	fmt.Println()
+	if !quietFlag {
+		green := color.New(color.FgGreen, color.Bold)
+		green.Printf("Parameters of '%s' updated successfully!\n", aliasName)
+	}
+	fmt.Println()
+	printAliasUsage(a)
+}
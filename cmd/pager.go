@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/mattn/go-isatty"
+
+	"aliasly/internal/config"
+)
+
+// startPager starts the user's pager and returns a writer that feeds it,
+// along with a function that closes the pager and waits for it to exit.
+//
+// Paging is used when requested is true or settings.use_pager is on,
+// and only when stdout is a terminal. Otherwise (or if the pager can't
+// be started) output goes straight to stdout.
+//
+// The pager is $PAGER, defaulting to "less -R" so colors are preserved.
+func startPager(requested bool) (io.Writer, func()) {
+	noPager := func() {}
+
+	if !requested {
+		cfg, err := config.Get()
+		if err != nil || !cfg.Settings.UsePager {
+			return os.Stdout, noPager
+		}
+	}
+
+	// Never page when output is redirected or piped
+	if !isatty.IsTerminal(os.Stdout.Fd()) {
+		return os.Stdout, noPager
+	}
+
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = "less -R"
+	}
+
+	// $PAGER may include arguments, so run it through the shell
+	var pagerCmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		pagerCmd = exec.Command("cmd", "/C", pager)
+	} else {
+		pagerCmd = exec.Command(config.GetDefaultShell(), "-c", pager)
+	}
+	pagerCmd.Stdout = os.Stdout
+	pagerCmd.Stderr = os.Stderr
+
+	stdin, err := pagerCmd.StdinPipe()
+	if err != nil {
+		return os.Stdout, noPager
+	}
+
+	if err := pagerCmd.Start(); err != nil {
+		return os.Stdout, noPager
+	}
+
+	return stdin, func() {
+		stdin.Close()
+		pagerCmd.Wait()
+	}
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"aliasly/internal/alias"
+)
+
+// previewCmd represents the preview command.
+// It prints every alias with its command expanded using example values.
+var previewCmd = &cobra.Command{
+	Use:   "preview",
+	Short: "Show every alias expanded with example values",
+	Long: `Show every alias with its command expanded using example values.
+
+Parameters are replaced with their default value, or <name> if they
+have none, so you see realistic forms like:
+  git commit -am "<message>"
+instead of the raw {{message}} placeholders.
+
+Examples:
+  al preview          # Print a readable catalog of aliases
+  al preview -o json  # Print name -> example pairs as JSON`,
+
+	Args: cobra.NoArgs,
+	Run:  runPreviewCmd,
+}
+
+// previewOutputFlag selects the output format (text or json)
+var previewOutputFlag string
+
+func init() {
+	rootCmd.AddCommand(previewCmd)
+	previewCmd.Flags().StringVarP(&previewOutputFlag, "output", "o", "text", "Output format: text or json")
+}
+
+func runPreviewCmd(cmd *cobra.Command, args []string) {
+	aliases, err := alias.GetAll()
+	if err != nil {
+		printError(fmt.Sprintf("Failed to load aliases: %v", err))
+		os.Exit(1)
+	}
+
+	switch previewOutputFlag {
+	case "json":
+		examples := make(map[string]string, len(aliases))
+		for _, a := range aliases {
+			examples[a.Name] = alias.FormatExample(a)
+		}
+
+		// Don't escape <param> placeholders as \u003c...\u003e
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(examples); err != nil {
+			printError(fmt.Sprintf("Failed to encode preview: %v", err))
+			os.Exit(1)
+		}
+
+	case "text":
+		nameColor := color.New(color.FgCyan, color.Bold)
+		cmdColor := color.New(color.FgGreen)
+		dimColor := color.New(color.Faint)
+
+		for _, a := range aliases {
+			nameColor.Printf("  al %s", alias.BuildUsageString(a))
+			if a.Description != "" {
+				dimColor.Printf(" - %s", a.Description)
+			}
+			fmt.Println()
+			cmdColor.Printf("    $ %s\n", alias.FormatExample(a))
+			fmt.Println()
+		}
+
+	default:
+		printError(fmt.Sprintf("Unknown output format '%s' (expected text or json)", previewOutputFlag))
+		os.Exit(1)
+	}
+}
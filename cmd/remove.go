@@ -15,7 +15,7 @@ import (
 // It deletes an existing alias after confirmation.
 var removeCmd = &cobra.Command{
 	// Use shows the expected arguments
-	Use: "remove <alias-name>",
+	Use: "remove <alias-name> | --all",
 
 	// Aliases for shorter typing
 	Aliases: []string{"rm", "delete", "del"},
@@ -27,21 +27,64 @@ var removeCmd = &cobra.Command{
 	Long: `Remove an existing alias from your configuration.
 
 You will be asked to confirm before the alias is deleted.
+Use --no-save to preview the removal without changing your config.
 
-Examples:
-  al remove gs     # Remove the 'gs' alias
-  al rm deploy     # Short form
-  al delete old    # Alternative form`,
+Use --all to remove every alias at once. This asks for confirmation
+unless --yes is given; add --keep-examples to re-add the starter aliases.
 
-	// Args validates that exactly one argument is provided
-	Args: cobra.ExactArgs(1),
+Examples:
+  al remove gs            # Remove the 'gs' alias
+  al rm deploy            # Short form
+  al delete old           # Alternative form
+  al rm gs --no-save      # Show what would be removed
+  al remove --all         # Remove all aliases`,
+
+	// Args validates that exactly one argument is provided,
+	// or none when removing everything with --all
+	Args: func(cmd *cobra.Command, args []string) error {
+		if removeAllFlag {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+
+	// Complete existing alias names
+	ValidArgsFunction: completeAliasNames,
 
 	// Run function
 	Run: runRemoveCmd,
 }
 
+// removeNoSaveFlag, when true, previews the removal without saving it
+var removeNoSaveFlag bool
+
+// removeJSONFlag, when true, prints the removed alias as JSON
+var removeJSONFlag bool
+
+// removeAllFlag, when true, removes every alias
+var removeAllFlag bool
+
+// removeYesFlag, when true, skips the confirmation for --all
+var removeYesFlag bool
+
+// removeKeepExamplesFlag, when true, re-adds the starter aliases after --all
+var removeKeepExamplesFlag bool
+
+func init() {
+	removeCmd.Flags().BoolVar(&removeNoSaveFlag, "no-save", false, "Preview the removal without saving it")
+	removeCmd.Flags().BoolVar(&removeJSONFlag, "json", false, "Print the removed alias as JSON")
+	removeCmd.Flags().BoolVar(&removeAllFlag, "all", false, "Remove all aliases")
+	removeCmd.Flags().BoolVarP(&removeYesFlag, "yes", "y", false, "Skip confirmation when using --all")
+	removeCmd.Flags().BoolVar(&removeKeepExamplesFlag, "keep-examples", false, "Re-add the starter aliases after --all")
+}
+
 // runRemoveCmd executes the remove command.
 func runRemoveCmd(cmd *cobra.Command, args []string) {
+	if removeAllFlag {
+		runRemoveAll()
+		return
+	}
+
 	// Get the alias name from arguments
 	aliasName := args[0]
 
@@ -53,6 +96,18 @@ func runRemoveCmd(cmd *cobra.Command, args []string) {
 		fmt.Println("Run 'al list' to see all available aliases")
 		os.Exit(1)
 	}
+	exitIfProjectAlias(aliasName)
+
+	// In preview mode, there's nothing to confirm since nothing is written
+	if removeNoSaveFlag {
+		if removeJSONFlag {
+			printAliasJSON(a)
+			return
+		}
+		fmt.Printf("[no-save] Would remove alias '%s'\n", a.Name)
+		fmt.Printf("Command: %s\n", a.Command)
+		return
+	}
 
 	// Show what we're about to delete
 	fmt.Printf("Alias: %s\n", a.Name)
@@ -70,7 +125,9 @@ func runRemoveCmd(cmd *cobra.Command, args []string) {
 	}
 
 	if !confirmed {
-		fmt.Println("Cancelled. Alias was not removed.")
+		if !quietFlag {
+			fmt.Println("Cancelled. Alias was not removed.")
+		}
 		return
 	}
 
@@ -80,9 +137,73 @@ func runRemoveCmd(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if removeJSONFlag {
+		printAliasJSON(a)
+		return
+	}
+
 	// Success message
-	green := color.New(color.FgGreen, color.Bold)
-	green.Printf("Alias '%s' removed successfully!\n", aliasName)
+	if !quietFlag {
+		green := color.New(color.FgGreen, color.Bold)
+		green.Printf("Alias '%s' removed successfully!\n", aliasName)
+	}
+}
+
+// runRemoveAll removes every alias after a strong confirmation.
+func runRemoveAll() {
+	aliases, err := alias.GetAll()
+	if err != nil {
+		printError(fmt.Sprintf("Failed to load aliases: %v", err))
+		os.Exit(1)
+	}
+
+	if len(aliases) == 0 && !removeKeepExamplesFlag {
+		fmt.Println("No aliases to remove.")
+		return
+	}
+
+	if removeNoSaveFlag {
+		fmt.Printf("[no-save] Would remove all %d alias(es)\n", len(aliases))
+		return
+	}
+
+	if !removeYesFlag {
+		// Make the user type the count so this can't be confirmed by accident
+		red := color.New(color.FgRed, color.Bold)
+		red.Printf("This will remove ALL %d alias(es)!\n", len(aliases))
+		fmt.Println()
+
+		expected := fmt.Sprintf("%d", len(aliases))
+		prompt := promptui.Prompt{
+			Label: fmt.Sprintf("Type %s to confirm", expected),
+		}
+
+		input, err := prompt.Run()
+		if err != nil {
+			handlePromptError(err)
+			return
+		}
+
+		if input != expected {
+			if !quietFlag {
+				fmt.Println("Cancelled. No aliases were removed.")
+			}
+			return
+		}
+	}
+
+	if err := alias.RemoveAll(removeKeepExamplesFlag); err != nil {
+		printError(fmt.Sprintf("Failed to remove aliases: %v", err))
+		os.Exit(1)
+	}
+
+	if !quietFlag {
+		green := color.New(color.FgGreen, color.Bold)
+		green.Printf("Removed %d alias(es)!\n", len(aliases))
+		if removeKeepExamplesFlag {
+			fmt.Println("Starter aliases have been re-added.")
+		}
+	}
 }
 
 // confirmDelete asks the user to confirm deletion.
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"aliasly/internal/alias"
+	"aliasly/internal/config"
+)
+
+// renameCmd represents the rename command.
+// It changes the name of an existing alias.
+var renameCmd = &cobra.Command{
+	Use:     "rename <old> <new>",
+	Aliases: []string{"mv"},
+	Short:   "Rename an alias",
+	Long: `Rename an alias.
+
+The alias keeps its place in the list and its usage stats.
+Aliases that reference it with {{alias:<old>}} are updated to use the
+new name. Project aliases can't be changed from here, so any that
+reference it are listed for you to fix in the project config.
+
+Examples:
+  al rename gs st    # Rename 'gs' to 'st'
+  al mv gp push      # Short form`,
+
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeAliasNames,
+	Run:               runRenameCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+}
+
+func runRenameCmd(cmd *cobra.Command, args []string) {
+	oldName, newName := args[0], args[1]
+
+	if _, found := alias.Find(oldName); !found {
+		printError(fmt.Sprintf("Alias '%s' not found", oldName))
+		os.Exit(1)
+	}
+	exitIfProjectAlias(oldName)
+
+	if err := alias.ValidateName(newName); err != nil {
+		printError(err.Error())
+		os.Exit(1)
+	}
+
+	if _, exists := alias.Find(newName); exists {
+		printError(fmt.Sprintf("Alias '%s' already exists", newName))
+		os.Exit(1)
+	}
+
+	// Aliases referencing the old name, which the rename updates
+	ref := "{{alias:" + oldName + "}}"
+	var updated, stale []string
+	if aliases, err := alias.GetAll(); err == nil {
+		for _, a := range aliases {
+			if a.Name == oldName || !strings.Contains(a.Command, ref) {
+				continue
+			}
+			if config.IsProjectAlias(a.Name) {
+				stale = append(stale, a.Name)
+			} else {
+				updated = append(updated, a.Name)
+			}
+		}
+	}
+
+	if err := alias.Rename(oldName, newName); err != nil {
+		printError(fmt.Sprintf("Failed to rename alias: %v", err))
+		os.Exit(1)
+	}
+
+	if !quietFlag {
+		green := color.New(color.FgGreen, color.Bold)
+		green.Printf("Renamed alias '%s' to '%s'\n", oldName, newName)
+		if len(updated) > 0 {
+			fmt.Printf("Updated references in: %s\n", strings.Join(updated, ", "))
+		}
+	}
+
+	// References in the project config now point at nothing
+	if len(stale) > 0 {
+		yellow := color.New(color.FgYellow)
+		yellow.Fprintf(os.Stderr, "Warning: These project aliases still reference %s: %s\n", ref, strings.Join(stale, ", "))
+	}
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"aliasly/internal/alias"
+)
+
+// paramNamePattern validates parameter names.
+// It matches what the {{param}} placeholder syntax accepts.
+var paramNamePattern = regexp.MustCompile(`^\w+$`)
+
+// renameParamCmd represents the rename-param command.
+// It renames a parameter and rewrites its placeholders in the command.
+var renameParamCmd = &cobra.Command{
+	Use:   "rename-param <alias> <old-param> <new-param>",
+	Short: "Rename a parameter of an alias",
+	Long: `Rename a parameter of an alias.
+
+This updates the parameter definition and rewrites every {{old-param}}
+placeholder in the command (and environment variables) to {{new-param}},
+keeping them in sync.
+
+Examples:
+  al rename-param gc message msg   # {{message}} becomes {{msg}}`,
+
+	Args:              cobra.ExactArgs(3),
+	ValidArgsFunction: completeAliasParams,
+	Run:               runRenameParamCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(renameParamCmd)
+}
+
+func runRenameParamCmd(cmd *cobra.Command, args []string) {
+	aliasName, oldName, newName := args[0], args[1], args[2]
+
+	a, found := alias.Find(aliasName)
+	if !found {
+		printError(fmt.Sprintf("Alias '%s' not found", aliasName))
+		os.Exit(1)
+	}
+	exitIfProjectAlias(aliasName)
+
+	if !paramNamePattern.MatchString(newName) {
+		printError("Parameter name can only contain letters, numbers, and underscores")
+		os.Exit(1)
+	}
+
+	// Find the parameter to rename and make sure the new name is free
+	index := -1
+	for i, p := range a.Params {
+		if p.Name == newName {
+			printError(fmt.Sprintf("Parameter '%s' already exists on alias '%s'", newName, aliasName))
+			os.Exit(1)
+		}
+		if p.Name == oldName {
+			index = i
+		}
+	}
+
+	if index == -1 {
+		printError(fmt.Sprintf("Parameter '%s' not found on alias '%s'", oldName, aliasName))
+		os.Exit(1)
+	}
+
+	// Copy the params so we don't modify the slice shared with the config
+	params := make([]alias.Param, len(a.Params))
+	copy(params, a.Params)
+	params[index].Name = newName
+	a.Params = params
+
+	a.Command = strings.ReplaceAll(a.Command, "{{"+oldName+"}}", "{{"+newName+"}}")
+
+	// Environment variable values can reference params too
+	if len(a.Env) > 0 {
+		env := make(map[string]string, len(a.Env))
+		for key, value := range a.Env {
+			env[key] = strings.ReplaceAll(value, "{{"+oldName+"}}", "{{"+newName+"}}")
+		}
+		a.Env = env
+	}
+
+	// Make sure the command and params are still consistent
+	if undefined := alias.ValidatePlaceholders(a); len(undefined) > 0 {
+		printError(fmt.Sprintf("Command has undefined placeholders: %s", strings.Join(undefined, ", ")))
+		os.Exit(1)
+	}
+
+	if err := alias.Update(a); err != nil {
+		printError(fmt.Sprintf("Failed to save alias: %v", err))
+		os.Exit(1)
+	}
+
+	if !quietFlag {
+		green := color.New(color.FgGreen, color.Bold)
+		green.Printf("Renamed parameter '%s' to '%s' in alias '%s'\n", oldName, newName, aliasName)
+	}
+	fmt.Printf("Command: %s\n", a.Command)
+}
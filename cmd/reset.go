@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+
+	"aliasly/internal/config"
+)
+
+// resetCmd represents the reset command.
+// It restores the default configuration. Like any save, the current
+// config is kept as a backup first.
+var resetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Restore the default configuration",
+	Long: `Restore the default configuration and starter aliases.
+
+Your current config is kept with the automatic backups, like on any
+change, so the reset can be undone with 'al restore'. Unlike
+'al uninstall', aliasly stays installed.
+
+Examples:
+  al reset        # Reset after confirmation
+  al reset --yes  # Reset without asking`,
+
+	Args: cobra.NoArgs,
+	Run:  runResetCmd,
+}
+
+// resetYesFlag, when true, skips the confirmation prompt
+var resetYesFlag bool
+
+func init() {
+	rootCmd.AddCommand(resetCmd)
+	resetCmd.Flags().BoolVarP(&resetYesFlag, "yes", "y", false, "Skip confirmation")
+}
+
+func runResetCmd(cmd *cobra.Command, args []string) {
+	if !resetYesFlag {
+		prompt := promptui.Select{
+			Label: "Replace your config with the default starter aliases?",
+			Items: []string{"No, cancel", "Yes, reset"},
+		}
+
+		idx, _, err := prompt.Run()
+		if err != nil {
+			handlePromptError(err)
+			return
+		}
+
+		if idx == 0 {
+			if !quietFlag {
+				fmt.Println("Cancelled.")
+			}
+			return
+		}
+	}
+
+	if err := config.Reset(); err != nil {
+		printError(fmt.Sprintf("Failed to reset config: %v", err))
+		os.Exit(1)
+	}
+
+	if !quietFlag {
+		green := color.New(color.FgGreen, color.Bold)
+		green.Println("Config reset to defaults!")
+		fmt.Println("Run 'al restore' to undo")
+	}
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+
+	"aliasly/internal/config"
+)
+
+// restoreCmd represents the restore command.
+// It rolls the config back to one of the automatic backups.
+var restoreCmd = &cobra.Command{
+	Use:   "restore [backup]",
+	Short: "Roll back to a previous version of the config",
+	Long: `Roll back to a previous version of the config.
+
+Every time the config changes, the previous version is copied to the
+backups directory in aliasly's state directory (~/.local/state/aliasly
+by default). The most recent 5 are kept; set
+'backup_count' under settings to keep more (or 0 to turn this off).
+
+Without an argument, you pick a backup from a list. The config being
+replaced is backed up too, so a restore can itself be undone.
+
+Examples:
+  al restore                                 # Pick a backup to restore
+  al restore --list                          # Show the available backups
+  al restore config-20250101-120000.000.yaml # Restore a specific backup`,
+
+	Args: cobra.MaximumNArgs(1),
+	Run:  runRestoreCmd,
+}
+
+// restoreListFlag, when true, only lists the backups
+var restoreListFlag bool
+
+// restoreYesFlag, when true, skips the confirmation prompt
+var restoreYesFlag bool
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+	restoreCmd.Flags().BoolVarP(&restoreListFlag, "list", "l", false, "List the available backups")
+	restoreCmd.Flags().BoolVarP(&restoreYesFlag, "yes", "y", false, "Skip confirmation")
+}
+
+func runRestoreCmd(cmd *cobra.Command, args []string) {
+	backups, err := config.ListBackups()
+	if err != nil {
+		printError(err.Error())
+		os.Exit(1)
+	}
+
+	var backupPath string
+	if len(args) == 1 {
+		// Accept either a path or a file name from the backups directory
+		backupPath = args[0]
+		if _, err := os.Stat(backupPath); os.IsNotExist(err) {
+			backupPath = filepath.Join(config.GetBackupsDir(), args[0])
+		}
+		if _, err := os.Stat(backupPath); err != nil {
+			printError(fmt.Sprintf("Backup '%s' not found", args[0]))
+			os.Exit(1)
+		}
+	} else {
+		if len(backups) == 0 {
+			fmt.Println("No backups yet.")
+			fmt.Printf("Backups are saved to %s whenever the config changes.\n", config.GetBackupsDir())
+			return
+		}
+
+		if restoreListFlag {
+			printBackups(backups)
+			return
+		}
+
+		items := make([]string, len(backups))
+		for i, b := range backups {
+			items[i] = describeBackup(b)
+		}
+
+		prompt := promptui.Select{
+			Label: "Select a backup to restore",
+			Items: items,
+			Size:  10,
+		}
+
+		idx, _, err := prompt.Run()
+		if err != nil {
+			handlePromptError(err)
+			return
+		}
+		backupPath = backups[idx].Path
+	}
+
+	if !restoreYesFlag {
+		prompt := promptui.Select{
+			Label: fmt.Sprintf("Replace your config with %s?", filepath.Base(backupPath)),
+			Items: []string{"No, cancel", "Yes, restore"},
+		}
+
+		idx, _, err := prompt.Run()
+		if err != nil {
+			handlePromptError(err)
+			return
+		}
+
+		if idx == 0 {
+			if !quietFlag {
+				fmt.Println("Cancelled.")
+			}
+			return
+		}
+	}
+
+	if err := config.RestoreBackup(backupPath); err != nil {
+		printError(fmt.Sprintf("Failed to restore backup: %v", err))
+		os.Exit(1)
+	}
+
+	if !quietFlag {
+		green := color.New(color.FgGreen, color.Bold)
+		green.Printf("Config restored from %s\n", filepath.Base(backupPath))
+	}
+}
+
+// printBackups prints the backups, newest first.
+func printBackups(backups []config.BackupInfo) {
+	dimColor := color.New(color.Faint)
+
+	fmt.Printf("Backups in %s:\n\n", config.GetBackupsDir())
+	for _, b := range backups {
+		fmt.Printf("  %s\n", filepath.Base(b.Path))
+		dimColor.Printf("    %s\n", describeBackup(b))
+	}
+}
+
+// describeBackup returns a one-line summary of a backup: when it was
+// taken and how many aliases it holds.
+func describeBackup(b config.BackupInfo) string {
+	summary := b.Time.Format("2006-01-02 15:04:05")
+
+	data, err := os.ReadFile(b.Path)
+	if err != nil {
+		return summary + " (unreadable)"
+	}
+	cfg, err := config.ParseFormat(data, config.FormatFromPath(b.Path))
+	if err != nil {
+		return summary + " (invalid)"
+	}
+
+	return fmt.Sprintf("%s - %d alias(es)", summary, len(cfg.Aliases))
+}
@@ -3,10 +3,15 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
+	"strings"
 
 	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 
 	"aliasly/internal/alias"
@@ -17,6 +22,33 @@ import (
 // This can be set at build time using -ldflags.
 var Version = "0.1.0"
 
+// Exit codes of 'al <alias>' and 'al run'. When the command runs,
+// aliasly exits with the command's own exit code; these codes report
+// failures before or around running it. They are a stable contract
+// for scripts and must not change.
+const (
+	// ExitError is a general error, e.g. the config can't be loaded,
+	// the alias is invalid, or a confirmation was declined
+	ExitError = 1
+
+	// ExitUsage means the arguments don't fit the alias, e.g. a missing
+	// required parameter or a value rejected by its choices, or an
+	// unknown flag was given
+	ExitUsage = 2
+
+	// ExitTimeout means the command was killed by its timeout
+	// (the same code as timeout(1))
+	ExitTimeout = 124
+
+	// ExitCannotRun means the command couldn't be started,
+	// e.g. the shell or interpreter doesn't exist
+	ExitCannotRun = 126
+
+	// ExitNotFound means there is no alias with the given name
+	// (the same code shells use for an unknown command)
+	ExitNotFound = 127
+)
+
 // rootCmd is the base command when called without any subcommands.
 // When the user runs just "al", this command's help is displayed.
 // When the user runs "al <something>", we check if <something> is:
@@ -42,6 +74,8 @@ You can create an alias and run:
 Examples:
   al gs              # Run the 'gs' alias (e.g., git status)
   al gc "message"    # Run 'gc' alias with a parameter
+  al gp branch=dev   # Pass a parameter by name (or --branch dev)
+  al --dry-run --raw gc "msg" | pbcopy  # Copy the expanded command
   al list            # List all configured aliases
   al add             # Interactively add a new alias
   al config          # Open web UI to manage aliases`,
@@ -53,6 +87,9 @@ Examples:
 	// We use ArbitraryArgs because we accept any number of arguments
 	Args: cobra.ArbitraryArgs,
 
+	// ValidArgsFunction completes alias names when pressing Tab
+	ValidArgsFunction: completeAliasArgs,
+
 	// SilenceUsage prevents printing usage on errors
 	// We handle our own error messages
 	SilenceUsage: true,
@@ -74,36 +111,102 @@ func runRootCmd(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	// The first argument should be the alias name
-	aliasName := args[0]
-
-	// Get the remaining arguments as parameters for the alias
-	// args[1:] gives us everything except the first element
-	params := args[1:]
+	// The first argument should be the alias name,
+	// the remaining arguments are parameters for the alias
+	runAlias(cmd, args[0], args[1:])
+}
 
+// runAlias looks up and executes an alias, then exits with the
+// command's exit code. It is shared by the root command and 'al run'
+// so both behave identically.
+func runAlias(cmd *cobra.Command, aliasName string, params []string) {
 	// Look up the alias
 	a, found := alias.Find(aliasName)
 	if !found {
 		// Alias not found - show a helpful error message
 		printError(fmt.Sprintf("Alias '%s' not found", aliasName))
-		fmt.Println()
-		fmt.Println("Run 'al list' to see available aliases")
-		fmt.Println("Run 'al add' to create a new alias")
-		os.Exit(1)
+
+		// Offer close matches in case it was a typo
+		if choice := suggestAlias(aliasName); choice != "" {
+			runAlias(cmd, choice, params)
+			return
+		}
+
+		if !quietFlag {
+			fmt.Println()
+			fmt.Println("Run 'al list' to see available aliases")
+			fmt.Println("Run 'al add' to create a new alias")
+		}
+		os.Exit(ExitNotFound)
 	}
 
 	// Run the alias with the provided parameters
-	exitCode, err := alias.Run(a, params)
+	loginShell, _ := cmd.Flags().GetBool("login-shell")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	raw, _ := cmd.Flags().GetBool("raw")
+	yes, _ := cmd.Flags().GetBool("yes")
+	// Without --verbose, Execute falls back to settings.verbose
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	noPrompt, _ := cmd.Flags().GetBool("no-prompt")
+	shell := shellFlag(cmd)
+	params = append(params, paramFlagArgs(cmd, a)...)
+
+	// Ask for missing required params instead of failing, when there's
+	// someone to ask. Scripts get the usual error, so they never hang.
+	if !noPrompt && isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stdout.Fd()) {
+		params = promptMissingParams(a, params)
+	}
+
+	warnExtraArgs(a, params)
+
+	// Aliases marked as dangerous need an explicit yes before running
+	if a.Confirm && !yes && !dryRun && !confirmRun(a, params) {
+		if !quietFlag {
+			fmt.Println("Cancelled.")
+		}
+		os.Exit(ExitError)
+	}
+	exitCode, err := alias.RunWithOptions(a, params, alias.ExecuteOptions{
+		Verbose:    verbose,
+		Quiet:      quietFlag,
+		Shell:      shell,
+		LoginShell: loginShell || a.LoginShell,
+		DryRun:     dryRun,
+		Raw:        raw,
+		AllowRoot:  yes,
+		// An alias marked with confirm was already confirmed above
+		SkipConfirm: yes || a.Confirm,
+		Confirm: func(command, pattern string) bool {
+			return confirmCommand(a.Name, command, fmt.Sprintf("The command matches the confirm pattern '%s'.", pattern))
+		},
+	})
+	if errors.Is(err, alias.ErrCancelled) {
+		if !quietFlag {
+			fmt.Println("Cancelled.")
+		}
+		os.Exit(ExitError)
+	}
 	if err != nil {
 		printError(err.Error())
 
-		// If it's a parse error (missing params), show usage help
-		if _, ok := err.(*alias.ParseError); ok {
+		switch err.(type) {
+		case *alias.ParseError:
+			// Missing or invalid params, so show usage help
 			fmt.Println()
 			printAliasUsage(a)
+			os.Exit(ExitUsage)
+		case *alias.TimeoutError:
+			os.Exit(ExitTimeout)
+		case *alias.StartError:
+			os.Exit(ExitCannotRun)
 		}
 
-		os.Exit(1)
+		os.Exit(ExitError)
+	}
+
+	// Surface the exit code on stderr so it doesn't pollute captured stdout
+	if printExit, _ := cmd.Flags().GetBool("print-exit"); printExit {
+		fmt.Fprintf(os.Stderr, "exit: %d\n", exitCode)
 	}
 
 	// Exit with the same exit code as the executed command
@@ -111,6 +214,222 @@ func runRootCmd(cmd *cobra.Command, args []string) {
 	os.Exit(exitCode)
 }
 
+// shellFlag returns the value of the --shell flag, exiting with a usage
+// error if the shell can't be found. It returns "" if the flag isn't set.
+func shellFlag(cmd *cobra.Command) string {
+	shell, _ := cmd.Flags().GetString("shell")
+	if shell == "" {
+		return ""
+	}
+
+	// LookPath accepts both a path and a name to find in PATH
+	if _, err := exec.LookPath(shell); err != nil {
+		printError(fmt.Sprintf("Shell '%s' not found or not executable", shell))
+		os.Exit(ExitUsage)
+	}
+	return shell
+}
+
+// paramFlagArgs turns the --param name=value flags into --name=value
+// arguments for the alias. They go after the alias's own arguments, so
+// they win over any value given there for the same parameter.
+// Exits with a usage error if a flag isn't name=value or names a
+// parameter the alias doesn't declare.
+func paramFlagArgs(cmd *cobra.Command, a alias.Alias) []string {
+	assignments, _ := cmd.Flags().GetStringArray("param")
+
+	declared := make(map[string]bool, len(a.Params))
+	for _, p := range a.Params {
+		declared[p.Name] = true
+	}
+
+	args := make([]string, 0, len(assignments))
+	for _, assignment := range assignments {
+		name, value, ok := strings.Cut(assignment, "=")
+		if !ok || name == "" {
+			printError(fmt.Sprintf("Invalid --param '%s': expected name=value", assignment))
+			os.Exit(ExitUsage)
+		}
+		if !declared[name] {
+			printError(fmt.Sprintf("Alias '%s' has no parameter '%s'", a.Name, name))
+			fmt.Println()
+			printAliasUsage(a)
+			os.Exit(ExitUsage)
+		}
+		args = append(args, "--"+name+"="+value)
+	}
+
+	return args
+}
+
+// promptMissingParams asks for the value of each required parameter
+// that wasn't given, labelled with its description, and returns params
+// with the answers added as --name=value arguments. Parameters with
+// choices are picked from a list; other values are checked against the
+// parameter's validation pattern as they are typed, and hidden if the
+// parameter is secret.
+//
+// If the arguments can't be matched to parameters, params is returned
+// unchanged so the usual error is reported when running the alias.
+func promptMissingParams(a alias.Alias, params []string) []string {
+	missing, err := alias.MissingParams(a, params)
+	if err != nil || len(missing) == 0 {
+		return params
+	}
+
+	for _, p := range missing {
+		label := p.Name
+		if p.Description != "" {
+			label = fmt.Sprintf("%s (%s)", p.Description, p.Name)
+		}
+
+		choices := p.Choices
+		if len(choices) == 0 && p.Type == config.ParamTypeBool {
+			choices = []string{"true", "false"}
+		}
+
+		var value string
+		if len(choices) > 0 {
+			prompt := promptui.Select{
+				Label: label,
+				Items: choices,
+			}
+			_, value, err = prompt.Run()
+		} else {
+			prompt := promptui.Prompt{
+				Label: label,
+				Validate: func(input string) error {
+					if input == "" {
+						return fmt.Errorf("%s is required", p.Name)
+					}
+					return alias.CheckValue(p, input)
+				},
+			}
+			if p.Secret {
+				// Keep secrets out of the terminal's scrollback
+				prompt.Mask = '*'
+			}
+			value, err = prompt.Run()
+		}
+		if err != nil {
+			handlePromptError(err)
+			os.Exit(ExitError)
+		}
+
+		params = append(params, "--"+p.Name+"="+value)
+	}
+
+	return params
+}
+
+// confirmRun shows the expanded command of an alias marked with
+// confirm and asks whether to run it. Without a terminal there's no one
+// to ask, so it refuses and tells the user to pass --yes.
+//
+// If the command can't be expanded (e.g. a missing parameter), it
+// returns true so the usual error is reported when running it.
+func confirmRun(a alias.Alias, params []string) bool {
+	command, err := alias.ParseCommandMasked(a, params)
+	if err != nil {
+		return true
+	}
+
+	return confirmCommand(a.Name, command, "")
+}
+
+// confirmCommand shows the expanded command of the named alias, with
+// an optional note saying why, and asks whether to run it. Without a
+// terminal it refuses and tells the user to pass --yes.
+func confirmCommand(name, command, note string) bool {
+	if !isatty.IsTerminal(os.Stdin.Fd()) {
+		printError(fmt.Sprintf("Alias '%s' requires confirmation; pass --yes to run it without a terminal", name))
+		os.Exit(ExitError)
+	}
+
+	yellow := color.New(color.FgYellow, color.Bold)
+	yellow.Printf("This will run:\n  $ %s\n\n", command)
+	if note != "" {
+		fmt.Printf("%s\n\n", note)
+	}
+
+	prompt := promptui.Select{
+		Label: fmt.Sprintf("Run '%s'?", name),
+		Items: []string{"No, cancel", "Yes, run it"},
+	}
+	idx, _, err := prompt.Run()
+	if err != nil {
+		return false
+	}
+
+	// idx 1 = "Yes, run it"
+	return idx == 1
+}
+
+// maxSuggestions is the most close matches offered for an unknown alias.
+const maxSuggestions = 5
+
+// suggestAlias prints the aliases whose names are close to name.
+// When running in a terminal, it lets the user pick one of them and
+// returns the chosen name; otherwise (or if they cancel) it returns "".
+// Scripts never get a prompt, so they aren't blocked.
+func suggestAlias(name string) string {
+	aliases, err := alias.GetAll()
+	if err != nil {
+		return ""
+	}
+
+	names := make([]string, len(aliases))
+	for i, a := range aliases {
+		names[i] = a.Name
+	}
+
+	suggestions := alias.SuggestNames(name, names)
+	if len(suggestions) == 0 {
+		return ""
+	}
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+
+	if !isatty.IsTerminal(os.Stdin.Fd()) || !isatty.IsTerminal(os.Stdout.Fd()) {
+		fmt.Printf("Did you mean '%s'?\n", suggestions[0])
+		return ""
+	}
+
+	fmt.Println()
+	prompt := promptui.Select{
+		Label: "Did you mean",
+		Items: append(suggestions, "Cancel"),
+	}
+	idx, _, err := prompt.Run()
+	if err != nil || idx == len(suggestions) {
+		return ""
+	}
+
+	return suggestions[idx]
+}
+
+// addRunFlags adds the flags that control how an alias is executed.
+// Flag parsing stops at the alias name, so that anything after it
+// (like --branch=dev) is passed to the alias as a parameter.
+func addRunFlags(cmd *cobra.Command) {
+	cmd.Flags().SetInterspersed(false)
+
+	cmd.Flags().Bool("login-shell", false, "Run the alias in a login shell so profile functions are available")
+	cmd.Flags().BoolP("dry-run", "n", false, "Print the expanded command instead of running it")
+	cmd.Flags().Bool("raw", false, "With --dry-run, print only the bare command (no banner), with secret values unmasked so it can be run")
+	cmd.Flags().Bool("print-exit", false, "Print the command's exit code to stderr after it runs")
+	cmd.Flags().BoolP("yes", "y", false, "Skip confirmation and root checks (confirm, refuse_root, warn_on_root)")
+	cmd.Flags().Bool("no-prompt", false, "Fail on missing required parameters instead of prompting for them")
+	cmd.Flags().StringArrayP("param", "p", nil, "Set a parameter as name=value, regardless of position (repeatable)")
+}
+
+// quietFlag, when true, leaves out aliasly's own messages (success
+// messages, headers, the verbose command echo). Errors and warnings
+// are still printed to stderr. It has no -q shorthand because
+// 'al list -q' is already --names-only.
+var quietFlag bool
+
 // printError prints an error message in red.
 func printError(message string) {
 	// color.Red is a convenience function from the fatih/color package
@@ -119,6 +438,41 @@ func printError(message string) {
 	red.Fprintf(os.Stderr, "Error: %s\n", message)
 }
 
+// exitIfProjectAlias exits with an error if name is an alias from the
+// project config, which aliasly doesn't change, before the user is
+// asked for anything.
+func exitIfProjectAlias(name string) {
+	if config.IsProjectAlias(name) {
+		printError(fmt.Sprintf("Alias '%s' comes from %s; edit that file instead", name, config.ProjectConfigPath()))
+		os.Exit(1)
+	}
+}
+
+// warnUnusedParams prints a warning if the alias declares parameters
+// it never uses. They don't stop the alias from being saved.
+func warnUnusedParams(a alias.Alias) {
+	if unused := alias.UnusedParams(a); len(unused) > 0 {
+		yellow := color.New(color.FgYellow)
+		yellow.Fprintf(os.Stderr, "Warning: Parameters not used in the command: %s\n", strings.Join(unused, ", "))
+	}
+}
+
+// warnExtraArgs prints a warning to stderr if some positional arguments
+// aren't taken by any parameter, which usually means a value with spaces
+// wasn't quoted. With settings.strict_args they're an error instead,
+// reported when the alias is parsed.
+func warnExtraArgs(a alias.Alias, params []string) {
+	if cfg, err := config.Get(); err == nil && cfg.Settings.StrictArgs {
+		return
+	}
+	extra, err := alias.ExtraArgs(a, params)
+	if err != nil || len(extra) == 0 {
+		return
+	}
+	yellow := color.New(color.FgYellow)
+	yellow.Fprintf(os.Stderr, "Warning: Ignoring extra arguments: %s (quote values that contain spaces)\n", strings.Join(extra, " "))
+}
+
 // printAliasUsage prints how to use a specific alias.
 func printAliasUsage(a alias.Alias) {
 	fmt.Printf("Usage: al %s\n", alias.BuildUsageString(a))
@@ -138,6 +492,12 @@ func printAliasUsage(a alias.Alias) {
 			} else if p.Default != "" {
 				requiredStr = fmt.Sprintf(" (default: %s)", p.Default)
 			}
+			if p.EnvVar != "" {
+				requiredStr += fmt.Sprintf(" (env: %s)", p.EnvVar)
+			}
+			if len(p.Choices) > 0 {
+				requiredStr += fmt.Sprintf(" [%s]", strings.Join(p.Choices, "|"))
+			}
 			fmt.Printf("  %-12s %s%s\n", p.Name, p.Description, requiredStr)
 		}
 	}
@@ -146,7 +506,15 @@ func printAliasUsage(a alias.Alias) {
 // Execute adds all child commands to the root command and runs the application.
 // This is called by main.main(). It only needs to happen once.
 func Execute() {
-	// Load configuration before running any commands
+	// Load configuration before running any commands.
+	// Shell completion is skipped, since it reads the completion cache
+	// and only loads the config if the cache is out of date.
+	if isCompletionRequest() {
+		if err := rootCmd.Execute(); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
 	if err := config.Load(); err != nil {
 		// If config can't be loaded, we still want to allow some commands
 		// like "al --version" or "al --help"
@@ -154,13 +522,45 @@ func Execute() {
 		fmt.Fprintf(os.Stderr, "Warning: Could not load config: %v\n", err)
 	}
 
-	// Execute the root command (this parses args and runs the appropriate command)
+	// Execute the root command (this parses args and runs the appropriate command).
+	// Commands report their own errors, so an error here means the
+	// arguments or flags couldn't be parsed.
 	if err := rootCmd.Execute(); err != nil {
 		printError(err.Error())
-		os.Exit(1)
+		os.Exit(ExitUsage)
 	}
 }
 
+// applyNoColorFlag turns off colored output for every command when
+// --no-color is given. fatih/color already turns it off by itself
+// when NO_COLOR is set or stdout isn't a terminal (e.g. piped to a
+// file), so those need no handling here.
+func applyNoColorFlag() {
+	if noColor, _ := rootCmd.PersistentFlags().GetBool("no-color"); noColor {
+		color.NoColor = true
+	}
+}
+
+// applyLocalFlag merges the project config's aliases into the global
+// ones when --local is given. The config is loaded before the flags are
+// parsed, so the project config is read at this point.
+func applyLocalFlag() {
+	local, _ := rootCmd.PersistentFlags().GetBool("local")
+	if !local {
+		return
+	}
+	if err := config.EnableProjectConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not load project config: %v\n", err)
+	}
+}
+
+// isCompletionRequest reports whether aliasly was invoked by a shell
+// completion script to get completions, rather than by the user.
+func isCompletionRequest() bool {
+	return len(os.Args) > 1 &&
+		(os.Args[1] == cobra.ShellCompRequestCmd || os.Args[1] == cobra.ShellCompNoDescRequestCmd)
+}
+
 // init is a special Go function that runs automatically when the package loads.
 // We use it to add subcommands to the root command.
 func init() {
@@ -173,4 +573,12 @@ func init() {
 	// Add global flags that apply to all commands
 	// These can be accessed from any subcommand
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Show commands before running them")
+	rootCmd.PersistentFlags().String("shell", "", "Shell to run aliases with, overriding settings.shell")
+	rootCmd.PersistentFlags().Bool("no-color", false, "Disable colored output")
+	rootCmd.PersistentFlags().BoolVar(&quietFlag, "quiet", false, "Only print the command's output and errors, not aliasly's own messages (no -q: 'al list -q' is --names-only)")
+	rootCmd.PersistentFlags().Bool("local", false, "Use aliases from the closest .aliasly.yaml, even if settings.project_config is off")
+	cobra.OnInitialize(applyNoColorFlag, applyLocalFlag)
+
+	// Flags that only apply when running an alias
+	addRunFlags(rootCmd)
 }
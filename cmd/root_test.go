@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// runCLIEnv, when set, makes the test binary act as the al command,
+// so tests can check what a real run prints and exits with.
+const runCLIEnv = "ALIASLY_TEST_RUN_CLI"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runCLIEnv) == "1" {
+		os.Args = append([]string{"al"}, os.Args[1:]...)
+		Execute()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// testCLIConfig is the config the CLI tests run against.
+const testCLIConfig = `version: 1
+settings:
+  shell: /bin/sh
+aliases:
+  - name: say
+    command: echo {{msg}}
+    params:
+      - name: msg
+        required: true
+  - name: fail
+    command: exit 3
+  - name: slow
+    command: sleep 5
+    timeout: 1
+  - name: badinterp
+    command: |
+      #!/nonexistent/interpreter
+      echo hi
+`
+
+// runCLI runs al with args against a temporary config holding
+// configData, and returns its stdout, stderr and exit code.
+func runCLI(t *testing.T, configData string, args ...string) (string, string, int) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the test config uses POSIX shell commands")
+	}
+
+	dir := t.TempDir()
+	configDir := filepath.Join(dir, "config")
+	if err := os.MkdirAll(configDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configData), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(),
+		runCLIEnv+"=1",
+		"ALIASLY_CONFIG_DIR="+configDir,
+		"ALIASLY_STATE_DIR="+filepath.Join(dir, "state"),
+		"NO_COLOR=1",
+	)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	code := 0
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code = exitErr.ExitCode()
+	} else if err != nil {
+		t.Fatal(err)
+	}
+
+	return stdout.String(), stderr.String(), code
+}
+
+func TestExitCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"success", []string{"say", "hi"}, 0},
+		{"command's own exit code", []string{"fail"}, 3},
+		{"unknown alias", []string{"nosuchalias"}, ExitNotFound},
+		{"missing parameter", []string{"--no-prompt", "say"}, ExitUsage},
+		{"unknown flag", []string{"--nosuchflag"}, ExitUsage},
+		{"unknown -p name after the alias", []string{"say", "-p", "nope=1"}, ExitUsage},
+		{"timeout", []string{"slow"}, ExitTimeout},
+		{"interpreter can't be started", []string{"badinterp"}, ExitCannotRun},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, stderr, code := runCLI(t, testCLIConfig, tt.args...)
+			if code != tt.want {
+				t.Errorf("al %v exited with %d, want %d\nstderr: %s", tt.args, code, tt.want, stderr)
+			}
+		})
+	}
+}
+
+func TestVerbose(t *testing.T) {
+	verboseConfig := strings.Replace(testCLIConfig, "  shell: /bin/sh\n", "  shell: /bin/sh\n  verbose: true\n", 1)
+
+	tests := []struct {
+		name   string
+		config string
+		args   []string
+		want   bool
+	}{
+		{"off by default", testCLIConfig, []string{"say", "hi"}, false},
+		{"--verbose flag", testCLIConfig, []string{"--verbose", "say", "hi"}, true},
+		{"-v flag", testCLIConfig, []string{"-v", "say", "hi"}, true},
+		{"settings.verbose", verboseConfig, []string{"say", "hi"}, true},
+		{"--quiet overrides settings.verbose", verboseConfig, []string{"--quiet", "say", "hi"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, code := runCLI(t, tt.config, tt.args...)
+			if code != 0 {
+				t.Fatalf("al %v exited with %d\nstderr: %s", tt.args, code, stderr)
+			}
+
+			printed := strings.Contains(stdout, "$ echo hi\n")
+			if printed != tt.want {
+				t.Errorf("al %v printed the command: %v, want %v\nstdout: %s", tt.args, printed, tt.want, stdout)
+			}
+			if !strings.HasSuffix(stdout, "hi\n") {
+				t.Errorf("al %v didn't run the command\nstdout: %s", tt.args, stdout)
+			}
+		})
+	}
+}
+
+func TestShowPorcelain(t *testing.T) {
+	stdout, stderr, code := runCLI(t, testCLIConfig, "show", "say", "--porcelain")
+	if code != 0 {
+		t.Fatalf("al show --porcelain exited with %d\nstderr: %s", code, stderr)
+	}
+
+	want := "say\techo {{msg}}\t\t\n"
+	if stdout != want {
+		t.Errorf("al show --porcelain = %q, want %q", stdout, want)
+	}
+}
+
+func TestQuietNotFound(t *testing.T) {
+	stdout, stderr, code := runCLI(t, testCLIConfig, "--quiet", "nosuchalias")
+	if code != ExitNotFound {
+		t.Fatalf("al --quiet nosuchalias exited with %d, want %d", code, ExitNotFound)
+	}
+	if stdout != "" {
+		t.Errorf("al --quiet printed hints to stdout: %q", stdout)
+	}
+	if !strings.Contains(stderr, "not found") {
+		t.Errorf("al --quiet didn't print the error to stderr: %q", stderr)
+	}
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// runCmd represents the run command.
+// It always executes the named alias, even if the name collides with
+// a built-in command like "list" or "add".
+var runCmd = &cobra.Command{
+	Use:   "run <alias> [params...]",
+	Short: "Run an alias explicitly",
+	Long: `Run an alias explicitly.
+
+'al <alias>' can't reach an alias named like a built-in command
+(e.g. "list" or "add"), because the built-in takes precedence.
+'al run' always runs the named alias, which also makes it a
+predictable choice for scripts.
+
+Examples:
+  al run gs              # Same as: al gs
+  al run list            # Runs an alias named 'list'
+  al run --dry-run gc "message"`,
+
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeAliasArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runAlias(cmd, args[0], args[1:])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+	addRunFlags(runCmd)
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"aliasly/internal/alias"
+)
+
+// searchCmd represents the search command.
+// It shows only the aliases matching all of the given terms.
+var searchCmd = &cobra.Command{
+	Use:   "search <term> [term...]",
+	Short: "Search aliases by name, command, or description",
+	Long: `Search aliases by name, command, or description.
+
+Matching is a case-insensitive substring match. When several terms are
+given, an alias must match all of them.
+
+Exits with status 1 if nothing matches, so it can be used in scripts.
+
+Examples:
+  al search git            # Aliases mentioning git
+  al search git push       # Aliases matching both 'git' and 'push'
+  al search --porcelain docker`,
+
+	Args: cobra.MinimumNArgs(1),
+	Run:  runSearchCmd,
+}
+
+// searchPorcelainFlag, when true, prints stable tab-separated output
+var searchPorcelainFlag bool
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().BoolVar(&searchPorcelainFlag, "porcelain", false, "Print stable, script-friendly tab-separated output")
+}
+
+func runSearchCmd(cmd *cobra.Command, args []string) {
+	aliases, err := alias.GetAll()
+	if err != nil {
+		printError(fmt.Sprintf("Failed to load aliases: %v", err))
+		os.Exit(1)
+	}
+
+	matches := searchAliases(aliases, args)
+
+	if searchPorcelainFlag {
+		printPorcelain(matches)
+		if len(matches) == 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(matches) == 0 {
+		fmt.Printf("No aliases match: %s\n", strings.Join(args, " "))
+		os.Exit(1)
+	}
+
+	fmt.Printf("Found %d matching alias(es):\n\n", len(matches))
+	for _, a := range matches {
+		printAlias(os.Stdout, a)
+	}
+}
+
+// searchAliases returns the aliases whose name, command, or description
+// contains every term, ignoring case.
+func searchAliases(aliases []alias.Alias, terms []string) []alias.Alias {
+	matches := make([]alias.Alias, 0)
+
+	for _, a := range aliases {
+		haystack := strings.ToLower(a.Name + "\n" + a.Command + "\n" + a.Description)
+
+		matchesAll := true
+		for _, term := range terms {
+			if !strings.Contains(haystack, strings.ToLower(term)) {
+				matchesAll = false
+				break
+			}
+		}
+
+		if matchesAll {
+			matches = append(matches, a)
+		}
+	}
+
+	return matches
+}
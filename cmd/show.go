@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"aliasly/internal/alias"
+	"aliasly/internal/config"
+)
+
+// showCmd represents the show command.
+// It prints everything about a single alias.
+var showCmd = &cobra.Command{
+	Use:     "show <alias>",
+	Aliases: []string{"info"},
+	Short:   "Show the full details of one alias",
+	Long: `Show the full details of one alias.
+
+Prints the command, description, every parameter with its settings,
+the usage string, and the command expanded with example values.
+Warns if the command uses placeholders that have no parameter.
+
+Long output can be paged with --pager, or always when 'use_pager: true'
+is set under settings. Use --porcelain for a single tab-separated line in the same format as
+'al list --porcelain': name, command, description and tags.
+
+Examples:
+  al show gc               # Show everything about the 'gc' alias
+  al info gc               # Same thing
+  al show gc --porcelain   # Stable tab-separated output for scripts
+  al show gc --pager       # Page the output through $PAGER`,
+
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAliasNames,
+	Run:               runShowCmd,
+}
+
+// showPorcelainFlag, when true, prints stable tab-separated output
+var showPorcelainFlag bool
+
+// showPagerFlag, when true, pipes the output through a pager
+var showPagerFlag bool
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+
+	showCmd.Flags().BoolVar(&showPorcelainFlag, "porcelain", false, "Print stable, script-friendly tab-separated output")
+	showCmd.Flags().BoolVar(&showPagerFlag, "pager", false, "Pipe output through $PAGER (default: less -R)")
+}
+
+func runShowCmd(cmd *cobra.Command, args []string) {
+	aliasName := args[0]
+
+	a, found := alias.Find(aliasName)
+	if !found {
+		printError(fmt.Sprintf("Alias '%s' not found", aliasName))
+		fmt.Println()
+		fmt.Println("Run 'al list' to see available aliases")
+		os.Exit(1)
+	}
+
+	// Porcelain output is the alias's line from 'al list --porcelain'
+	if showPorcelainFlag {
+		printPorcelain([]alias.Alias{a})
+		return
+	}
+
+	// Page the output if requested and stdout is a terminal
+	w, closePager := startPager(showPagerFlag)
+	defer closePager()
+
+	nameColor := color.New(color.FgCyan, color.Bold)
+	cmdColor := color.New(color.FgGreen)
+	dimColor := color.New(color.Faint)
+	yellow := color.New(color.FgYellow)
+
+	nameColor.Fprintln(w, a.Name)
+	if a.Description != "" {
+		fmt.Fprintf(w, "  %s\n", a.Description)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "Command:")
+	cmdColor.Fprintf(w, "  $ %s\n", a.Command)
+
+	if a.WorkingDir != "" {
+		fmt.Fprintf(w, "Directory: %s\n", a.WorkingDir)
+	}
+	if config.IsProjectAlias(a.Name) {
+		fmt.Fprintf(w, "Source:    %s\n", config.ProjectConfigPath())
+	}
+	if len(a.Tags) > 0 {
+		fmt.Fprintf(w, "Tags:      %s\n", strings.Join(a.Tags, ", "))
+	}
+
+	// Print the environment variables sorted by name
+	if len(a.Env) > 0 {
+		keys := make([]string, 0, len(a.Env))
+		for key := range a.Env {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Environment:")
+		for _, key := range keys {
+			fmt.Fprintf(w, "  %s=%s\n", key, a.Env[key])
+		}
+	}
+
+	if len(a.Params) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Parameters:")
+		for _, p := range a.Params {
+			details := make([]string, 0, 4)
+			if p.Required {
+				details = append(details, "required")
+			} else {
+				details = append(details, "optional")
+			}
+			if p.Type != "" && p.Type != config.ParamTypeString {
+				details = append(details, p.Type)
+			}
+			if p.MustExist {
+				details = append(details, "must exist")
+			}
+			if p.EnvVar != "" {
+				details = append(details, "env: "+p.EnvVar)
+			}
+			if p.Default != "" {
+				details = append(details, "default: "+p.Default)
+			}
+			if p.Variadic {
+				details = append(details, "variadic")
+			}
+			if p.Secret {
+				details = append(details, "secret")
+			}
+			if len(p.Choices) > 0 {
+				details = append(details, "one of: "+strings.Join(p.Choices, "|"))
+			}
+			if p.Validate != "" {
+				details = append(details, "must match: "+p.Validate)
+			}
+
+			fmt.Fprintf(w, "  %-12s ", p.Name)
+			dimColor.Fprintf(w, "(%s)", strings.Join(details, ", "))
+			if p.Description != "" {
+				fmt.Fprintf(w, " %s", p.Description)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+
+	if a.Notes != "" {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Notes:")
+		for _, line := range strings.Split(strings.TrimRight(a.Notes, "\n"), "\n") {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Usage:   al %s\n", alias.BuildUsageString(a))
+	fmt.Fprintf(w, "Example: %s\n", alias.FormatExample(a))
+
+	// Point out placeholders that will never be substituted
+	if undefined := alias.ValidatePlaceholders(a); len(undefined) > 0 {
+		fmt.Fprintln(w)
+		yellow.Fprintf(w, "Warning: Command has undefined placeholders: %s\n", strings.Join(undefined, ", "))
+		fmt.Fprintf(w, "Run 'al edit %s' to add the missing parameters\n", a.Name)
+	}
+	if unused := alias.UnusedParams(a); len(unused) > 0 {
+		fmt.Fprintln(w)
+		yellow.Fprintf(w, "Warning: Parameters not used in the command: %s\n", strings.Join(unused, ", "))
+	}
+}
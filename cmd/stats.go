@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"aliasly/internal/alias"
+	"aliasly/internal/config"
+)
+
+// statsCmd represents the stats command.
+// It shows how often each alias has been run.
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show how often each alias has been run",
+	Long: `Show how many times each alias has been run and when it was last used.
+
+Aliases are sorted from most to least used, so the ones you never touch
+end up at the bottom and are easy to prune.
+
+Run counts are stored in stats.yaml in aliasly's state directory
+(~/.local/state/aliasly by default).
+Set 'track_usage: false' under settings to stop recording them.
+
+Examples:
+  al stats`,
+
+	Args: cobra.NoArgs,
+	Run:  runStatsCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+}
+
+// aliasStats pairs an alias name with its usage stats for sorting.
+type aliasStats struct {
+	name  string
+	stats config.UsageStats
+}
+
+func runStatsCmd(cmd *cobra.Command, args []string) {
+	aliases, err := alias.GetAll()
+	if err != nil {
+		printError(fmt.Sprintf("Failed to load aliases: %v", err))
+		os.Exit(1)
+	}
+
+	stats, err := config.LoadStats()
+	if err != nil {
+		printError(fmt.Sprintf("Failed to load stats: %v", err))
+		os.Exit(1)
+	}
+
+	if cfg, err := config.Get(); err == nil && !cfg.Settings.UsageTrackingEnabled() {
+		fmt.Println("Usage tracking is turned off (track_usage: false).")
+		fmt.Println()
+	}
+
+	if len(aliases) == 0 {
+		fmt.Println("No aliases configured yet.")
+		return
+	}
+
+	// Only show aliases that still exist; stats for removed
+	// aliases are left in the file but ignored
+	rows := make([]aliasStats, 0, len(aliases))
+	nameWidth := len("NAME")
+	for _, a := range aliases {
+		rows = append(rows, aliasStats{name: a.Name, stats: stats[a.Name]})
+		if len(a.Name) > nameWidth {
+			nameWidth = len(a.Name)
+		}
+	}
+
+	// Most used first, ties broken by name
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].stats.Count != rows[j].stats.Count {
+			return rows[i].stats.Count > rows[j].stats.Count
+		}
+		return rows[i].name < rows[j].name
+	})
+
+	headerColor := color.New(color.Bold)
+	nameColor := color.New(color.FgCyan, color.Bold)
+	dimColor := color.New(color.Faint)
+
+	headerColor.Printf("  %-*s  %6s  %s\n", nameWidth, "NAME", "RUNS", "LAST USED")
+	for _, row := range rows {
+		nameColor.Printf("  %-*s", nameWidth, row.name)
+		fmt.Printf("  %6d  ", row.stats.Count)
+		if row.stats.LastUsed.IsZero() {
+			dimColor.Println("never")
+		} else {
+			fmt.Println(row.stats.LastUsed.Local().Format("2006-01-02 15:04"))
+		}
+	}
+}
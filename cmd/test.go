@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"aliasly/internal/alias"
+)
+
+// testCmd represents the test command.
+// It checks that every alias expands cleanly, without running anything.
+var testCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Check that every alias expands correctly",
+	Long: `Check that every alias expands correctly, without running anything.
+
+Each alias is expanded with example values for its parameters (the
+default value, or <name> if there is none). An alias fails if the
+expansion returns an error or leaves {{placeholders}} behind.
+
+Exits with a non-zero status if any alias fails, so it can be used
+as a regression check in CI.
+
+Examples:
+  al test    # Check all aliases`,
+
+	Args: cobra.NoArgs,
+	Run:  runTestCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(testCmd)
+}
+
+func runTestCmd(cmd *cobra.Command, args []string) {
+	aliases, err := alias.GetAll()
+	if err != nil {
+		printError(fmt.Sprintf("Failed to load aliases: %v", err))
+		os.Exit(1)
+	}
+
+	green := color.New(color.FgGreen, color.Bold)
+	red := color.New(color.FgRed, color.Bold)
+	dimColor := color.New(color.Faint)
+
+	failed := 0
+	for _, a := range aliases {
+		expanded, err := testAlias(a)
+		if err != nil {
+			failed++
+			red.Print("  FAIL ")
+			fmt.Printf("%s: %v\n", a.Name, err)
+			continue
+		}
+
+		green.Print("  PASS ")
+		fmt.Print(a.Name)
+		dimColor.Printf("  $ %s\n", expanded)
+	}
+
+	fmt.Println()
+	fmt.Printf("%d passed, %d failed\n", len(aliases)-failed, failed)
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// testAlias expands an alias with example values and returns the
+// resulting command, or an error if it doesn't fully expand.
+func testAlias(a alias.Alias) (string, error) {
+	// Pass one example value per parameter, positionally
+	exampleArgs := make([]string, 0, len(a.Params))
+	for _, p := range a.Params {
+		if p.Default != "" {
+			exampleArgs = append(exampleArgs, p.Default)
+		} else {
+			exampleArgs = append(exampleArgs, "<"+p.Name+">")
+		}
+	}
+
+	expanded, err := alias.ParseCommand(a, exampleArgs)
+	if err != nil {
+		return "", err
+	}
+
+	if leftover := alias.ExtractPlaceholders(expanded); len(leftover) > 0 {
+		return "", fmt.Errorf("unexpanded placeholders: %s", strings.Join(leftover, ", "))
+	}
+
+	return expanded, nil
+}
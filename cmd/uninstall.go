@@ -54,7 +54,9 @@ func runUninstallCmd(cmd *cobra.Command, args []string) {
 
 	idx, _, err := confirmPrompt.Run()
 	if err != nil || idx == 0 {
-		fmt.Println("Cancelled.")
+		if !quietFlag {
+			fmt.Println("Cancelled.")
+		}
 		return
 	}
 
@@ -73,7 +75,7 @@ func runUninstallCmd(cmd *cobra.Command, args []string) {
 		idx, _, err := removeShellPrompt.Run()
 		if err == nil && idx == 0 {
 			if err := removeShellIntegration(shellConfig); err != nil {
-				yellow.Printf("Warning: Could not remove shell integration: %v\n", err)
+				yellow.Fprintf(os.Stderr, "Warning: Could not remove shell integration: %v\n", err)
 			} else {
 				green.Println("Shell integration removed.")
 			}
@@ -95,10 +97,15 @@ func runUninstallCmd(cmd *cobra.Command, args []string) {
 		if err == nil && idx == 1 {
 			configDir := config.GetConfigDir()
 			if err := os.RemoveAll(configDir); err != nil {
-				yellow.Printf("Warning: Could not remove config: %v\n", err)
+				yellow.Fprintf(os.Stderr, "Warning: Could not remove config: %v\n", err)
 			} else {
 				green.Println("Config file removed.")
 			}
+
+			// Stats, history and backups live in the state directory
+			if err := os.RemoveAll(config.GetStateDir()); err != nil {
+				yellow.Fprintf(os.Stderr, "Warning: Could not remove stats and history: %v\n", err)
+			}
 		}
 		fmt.Println()
 	}
@@ -116,7 +123,7 @@ func runUninstallCmd(cmd *cobra.Command, args []string) {
 		idx, _, err := removeBinaryPrompt.Run()
 		if err == nil && idx == 0 {
 			if err := removeBinary(binaryPath); err != nil {
-				yellow.Printf("Warning: Could not remove binary: %v\n", err)
+				yellow.Fprintf(os.Stderr, "Warning: Could not remove binary: %v\n", err)
 				fmt.Println("You can remove it manually with:")
 				fmt.Printf("  sudo rm %s\n", binaryPath)
 			} else {
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"aliasly/internal/alias"
+	"aliasly/internal/config"
+)
+
+// validateCmd represents the validate command.
+// It checks every alias in a config file and reports all problems at once.
+var validateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Check the config for problems",
+	Long: `Check every alias in the config and report all problems in one pass.
+
+Errors:
+  - Empty commands
+  - Duplicate alias names
+  - Names that don't follow the name policy
+  - Placeholders with no matching parameter
+  - Parameters that are required but also have a default
+  - Invalid variadic parameters, types, choices or validation patterns
+
+Warnings:
+  - Parameters that are declared but never used
+  - Names that match a command in $PATH (skip with --no-warn)
+
+The command exits non-zero if there are errors (or warnings, with
+--strict), so it can run in CI. Pass a file to check it instead of
+your own config, e.g. a config kept in a dotfiles repository.
+
+Examples:
+  al validate                       # Check your config
+  al validate --strict              # Fail on warnings too
+  al validate dotfiles/aliasly.yaml # Check another config file`,
+
+	Args: cobra.MaximumNArgs(1),
+	Run:  runValidateCmd,
+}
+
+// validateStrictFlag, when true, treats warnings as errors
+var validateStrictFlag bool
+
+// validateNoWarnFlag, when true, skips checking alias names against $PATH
+var validateNoWarnFlag bool
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	validateCmd.Flags().BoolVar(&validateStrictFlag, "strict", false, "Exit non-zero on warnings too")
+	validateCmd.Flags().BoolVar(&validateNoWarnFlag, "no-warn", false, "Don't warn about names that match commands in $PATH")
+}
+
+func runValidateCmd(cmd *cobra.Command, args []string) {
+	path := config.GetConfigFilePath()
+	if len(args) == 1 {
+		path = args[0]
+	}
+
+	// Read the file directly rather than through config.Get, so a
+	// missing config isn't replaced by the defaults and duplicate
+	// names are seen as they are in the file
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && len(args) == 0 {
+		fmt.Printf("No config file at %s yet, nothing to validate.\n", path)
+		return
+	}
+	if err != nil {
+		printError(fmt.Sprintf("Failed to read %s: %v", path, err))
+		os.Exit(1)
+	}
+
+	cfg, err := config.ParseFormat(data, config.FormatFromPath(path))
+	if err != nil {
+		printError(err.Error())
+		os.Exit(1)
+	}
+
+	red := color.New(color.FgRed, color.Bold)
+	yellow := color.New(color.FgYellow, color.Bold)
+	nameColor := color.New(color.FgCyan, color.Bold)
+
+	issues := alias.ValidateConfig(cfg)
+	if !validateNoWarnFlag {
+		issues = append(issues, alias.ShadowIssues(cfg)...)
+		sort.SliceStable(issues, func(i, j int) bool {
+			return issues[i].Index < issues[j].Index
+		})
+	}
+
+	errorCount, warningCount := 0, 0
+	lastIndex := -1
+
+	// Issues come in alias order, so print a header whenever the alias changes
+	for _, issue := range issues {
+		if issue.Index != lastIndex {
+			if lastIndex >= 0 {
+				fmt.Println()
+			}
+			name := issue.Alias
+			if name == "" {
+				name = fmt.Sprintf("(alias #%d)", issue.Index+1)
+			}
+			nameColor.Printf("  %s\n", name)
+			lastIndex = issue.Index
+		}
+
+		if issue.IsError() {
+			red.Print("    error    ")
+			errorCount++
+		} else {
+			yellow.Print("    warning  ")
+			warningCount++
+		}
+		fmt.Println(issue.Message)
+	}
+	if lastIndex >= 0 {
+		fmt.Println()
+	}
+
+	fmt.Printf("Checked %d alias(es) in %s: %d error(s), %d warning(s)\n",
+		len(cfg.Aliases), path, errorCount, warningCount)
+
+	if errorCount > 0 || (validateStrictFlag && warningCount > 0) {
+		os.Exit(1)
+	}
+}
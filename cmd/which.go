@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"aliasly/internal/alias"
+)
+
+// whichCmd represents the which command.
+// It prints the command an alias would run, without running it.
+var whichCmd = &cobra.Command{
+	Use:   "which <alias> [params...]",
+	Short: "Print the command an alias would run",
+	Long: `Print the command an alias would run with the given parameters,
+along with the shell and working directory it would run in, and exit
+without running it.
+
+Unlike --dry-run, the command is printed on its own so it can be copied,
+and the shell, working directory and environment are shown too. Values
+of secret parameters are masked unless you pass --show-secrets.
+
+Examples:
+  al which gs
+  al which gc "fix bug"
+  al which --login-shell deploy staging`,
+
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeAliasArgs,
+	Run:               runWhichCmd,
+}
+
+// whichLoginShellFlag, when true, shows the login shell invocation
+var whichLoginShellFlag bool
+
+// whichShowSecretsFlag, when true, prints secret values unmasked
+var whichShowSecretsFlag bool
+
+func init() {
+	rootCmd.AddCommand(whichCmd)
+
+	// Stop at the alias name, so its parameters (like --branch=dev)
+	// aren't parsed as flags of this command
+	whichCmd.Flags().SetInterspersed(false)
+	whichCmd.Flags().BoolVar(&whichLoginShellFlag, "login-shell", false, "Show the command as run in a login shell")
+	whichCmd.Flags().BoolVar(&whichShowSecretsFlag, "show-secrets", false, "Print the values of secret parameters")
+}
+
+func runWhichCmd(cmd *cobra.Command, args []string) {
+	a, found := alias.Find(args[0])
+	if !found {
+		printError(fmt.Sprintf("Alias '%s' not found", args[0]))
+		os.Exit(ExitNotFound)
+	}
+	params := args[1:]
+
+	parseCommand, parseEnv := alias.ParseCommandMasked, alias.ParseEnvMasked
+	if whichShowSecretsFlag {
+		parseCommand, parseEnv = alias.ParseCommand, alias.ParseEnv
+	}
+
+	command, err := parseCommand(a, params)
+	if err != nil {
+		printError(err.Error())
+		if _, ok := err.(*alias.ParseError); ok {
+			fmt.Println()
+			printAliasUsage(a)
+			os.Exit(ExitUsage)
+		}
+		os.Exit(ExitError)
+	}
+
+	env, err := parseEnv(a, params)
+	if err != nil {
+		printError(err.Error())
+		os.Exit(ExitError)
+	}
+
+	fmt.Println(strings.TrimRight(command, "\n"))
+	fmt.Println()
+
+	labelColor := color.New(color.Bold)
+	dimColor := color.New(color.Faint)
+	yellow := color.New(color.FgYellow)
+
+	labelColor.Print("Shell:       ")
+	if interpreter, ok := alias.ParseShebang(command); ok {
+		fmt.Printf("%s ", strings.Join(interpreter, " "))
+		dimColor.Println("(from the shebang line)")
+	} else {
+		shell := alias.ResolveShell(shellFlag(cmd))
+		if whichLoginShellFlag || a.LoginShell {
+			fmt.Printf("%s ", shell)
+			dimColor.Println("(login shell)")
+		} else {
+			fmt.Println(shell)
+		}
+	}
+
+	labelColor.Print("Working dir: ")
+	if a.WorkingDir != "" {
+		if dir, err := alias.ResolveWorkingDir(a.WorkingDir); err != nil {
+			yellow.Println(err.Error())
+		} else {
+			fmt.Println(dir)
+		}
+	} else {
+		wd, _ := os.Getwd()
+		fmt.Printf("%s ", wd)
+		dimColor.Println("(current directory)")
+	}
+
+	if len(env) > 0 {
+		labelColor.Println("Environment:")
+		for _, kv := range env {
+			fmt.Printf("  %s\n", kv)
+		}
+	}
+}
@@ -3,6 +3,8 @@
 package alias
 
 import (
+	"strings"
+
 	"aliasly/internal/config"
 )
 
@@ -27,8 +29,12 @@ func GetAll() ([]Alias, error) {
 }
 
 // Add creates a new alias.
-// Returns an error if the alias name is already taken.
+// Returns an error if the alias name is already taken, or an
+// *UndefinedPlaceholdersError if the command uses undefined placeholders.
 func Add(alias Alias) error {
+	if err := CheckPlaceholders(alias); err != nil {
+		return err
+	}
 	return config.AddAlias(alias)
 }
 
@@ -38,9 +44,31 @@ func Remove(name string) error {
 	return config.RemoveAlias(name)
 }
 
+// RemoveMany deletes the named aliases in a single save, skipping names
+// that don't exist. It returns the names removed and those not found.
+func RemoveMany(names []string) (removed, notFound []string, err error) {
+	return config.RemoveAliases(names)
+}
+
+// RemoveAll deletes every alias.
+// If keepExamples is true, the default starter aliases are re-added.
+func RemoveAll(keepExamples bool) error {
+	return config.ClearAliases(keepExamples)
+}
+
+// Rename changes an alias's name, keeping its position and usage stats.
+// Returns an error if the alias doesn't exist or the new name is taken.
+func Rename(oldName, newName string) error {
+	return config.RenameAlias(oldName, newName)
+}
+
 // Update modifies an existing alias.
-// Returns an error if the alias doesn't exist.
+// Returns an error if the alias doesn't exist, or an
+// *UndefinedPlaceholdersError if the command uses undefined placeholders.
 func Update(alias Alias) error {
+	if err := CheckPlaceholders(alias); err != nil {
+		return err
+	}
 	return config.UpdateAlias(alias)
 }
 
@@ -79,16 +107,51 @@ func GetOptionalParams(a Alias) []Param {
 // BuildUsageString creates a usage string for an alias.
 // Example: "gc <message>" or "gp [branch]"
 // Required params are shown in <angle brackets>, optional in [square brackets].
+// Variadic params get a trailing "...", e.g. "drun [args...]".
 func BuildUsageString(a Alias) string {
 	usage := a.Name
 
 	for _, p := range a.Params {
+		name := p.Name
+		if p.Variadic {
+			name += "..."
+		}
+
 		if p.Required {
-			usage += " <" + p.Name + ">"
+			usage += " <" + name + ">"
 		} else {
-			usage += " [" + p.Name + "]"
+			usage += " [" + name + "]"
 		}
 	}
 
 	return usage
 }
+
+// BuildExampleInvocation creates a ready-to-copy invocation for an alias.
+// Parameters are filled with their default value, or <name> if they
+// have none. Trailing optional parameters without a default are left
+// out, since they can be omitted.
+//
+// Example output: "gp main" instead of "gp [branch]"
+func BuildExampleInvocation(a Alias) string {
+	// Find the last param that needs a value in the example
+	last := -1
+	for i, p := range a.Params {
+		if p.Required || p.Default != "" {
+			last = i
+		}
+	}
+
+	invocation := a.Name
+	for _, p := range a.Params[:last+1] {
+		if p.Default == "" {
+			invocation += " <" + p.Name + ">"
+		} else if strings.ContainsAny(p.Default, " \t\n'\"$`\\|&;<>()*?#~") {
+			invocation += " " + ShellQuote(p.Default)
+		} else {
+			invocation += " " + p.Default
+		}
+	}
+
+	return invocation
+}
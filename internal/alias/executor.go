@@ -1,10 +1,18 @@
 package alias
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"os/signal"
+	"path/filepath"
 	"runtime"
+	"strings"
+	"syscall"
+	"time"
 
 	"aliasly/internal/config"
 )
@@ -18,9 +26,109 @@ type ExecuteOptions struct {
 	// Verbose, when true, prints the command before executing it.
 	Verbose bool
 
+	// Quiet, when true, never prints the command before executing it,
+	// even with settings.verbose. Dry runs still print it.
+	Quiet bool
+
+	// DisplayCommand, if not empty, is printed instead of the command
+	// in verbose and dry-run output, e.g. with secret values masked.
+	DisplayCommand string
+
 	// DryRun, when true, prints the command but doesn't execute it.
 	// Useful for testing what a command would do.
 	DryRun bool
+
+	// Raw, when combined with DryRun, prints only the bare expanded
+	// command with no banner, so the output can be piped elsewhere.
+	// It prints the real command rather than DisplayCommand, so secret
+	// values are not masked: the output is meant to be run.
+	Raw bool
+
+	// LoginShell, when true, runs the shell as a login shell (-l -c)
+	// so the user's profile is sourced before the command runs.
+	LoginShell bool
+
+	// WorkingDir is the directory to run the command in.
+	// ~ and environment variables are expanded. If empty, the
+	// current directory is used.
+	WorkingDir string
+
+	// Env holds extra KEY=value environment variables for the command.
+	// They are appended after the inherited environment, so they win
+	// over inherited variables with the same name.
+	Env []string
+
+	// Timeout is how long the command may run before it is killed.
+	// If zero, the global timeout setting is used (0 there means none).
+	Timeout time.Duration
+
+	// TimeoutSignal is the signal name (e.g. "SIGTERM") sent when the
+	// timeout fires. If empty, the command is killed immediately.
+	TimeoutSignal string
+
+	// TimeoutGrace is how long to wait after TimeoutSignal before
+	// killing the command if it is still running.
+	TimeoutGrace time.Duration
+
+	// AllowRoot, when true, runs the alias as root even if the alias
+	// sets refuse_root or the warn_on_root setting is on.
+	AllowRoot bool
+
+	// SkipConfirm, when true, runs the command without confirmation
+	// even if it matches one of the confirm_patterns settings.
+	SkipConfirm bool
+
+	// Confirm is called when the expanded command matches one of the
+	// confirm_patterns settings, with the command (secret values
+	// masked) and the pattern it matched. The command only runs if it returns true. If nil, such
+	// commands aren't run unless SkipConfirm is set.
+	Confirm func(command, pattern string) bool
+
+	// Stdin, Stdout and Stderr are the command's standard streams.
+	// If nil, the terminal's (os.Stdin, os.Stdout, os.Stderr) are used.
+	// Dry-run and verbose output also go to Stdout and Stderr.
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// Context, if set, stops the command and everything it started
+	// when it is done, e.g. when the client of a web request goes away.
+	Context context.Context
+}
+
+// ErrCancelled is returned by RunWithOptions when the Confirm callback
+// declines to run a command.
+var ErrCancelled = errors.New("cancelled")
+
+// TimeoutError is returned by Execute when a command was killed
+// because it ran longer than its timeout.
+// This lets callers tell a timeout apart from a normal non-zero exit.
+type TimeoutError struct {
+	// Timeout is the limit that was exceeded
+	Timeout time.Duration
+}
+
+// Error implements the error interface for TimeoutError.
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("command timed out after %s", e.Timeout)
+}
+
+// StartError is returned by Execute when the command couldn't be
+// started at all, e.g. because the shell or interpreter doesn't exist.
+// This lets callers tell it apart from a command that ran and failed.
+type StartError struct {
+	// Err is the underlying error from starting the process
+	Err error
+}
+
+// Error implements the error interface for StartError.
+func (e *StartError) Error() string {
+	return fmt.Sprintf("failed to execute command: %v", e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *StartError) Unwrap() error {
+	return e.Err
 }
 
 // Execute runs a command string in the shell.
@@ -34,17 +142,7 @@ type ExecuteOptions struct {
 // couldn't be started.
 func Execute(command string, opts ExecuteOptions) (int, error) {
 	// Determine which shell to use
-	shell := opts.Shell
-	if shell == "" {
-		// Try to get shell from config
-		cfg, err := config.Get()
-		if err == nil && cfg.Settings.Shell != "" {
-			shell = cfg.Settings.Shell
-		} else {
-			// Fall back to system default
-			shell = config.GetDefaultShell()
-		}
-	}
+	shell := ResolveShell(opts.Shell)
 
 	// Check verbose setting from config if not explicitly set
 	verbose := opts.Verbose
@@ -54,45 +152,167 @@ func Execute(command string, opts ExecuteOptions) (int, error) {
 			verbose = cfg.Settings.Verbose
 		}
 	}
+	if opts.Quiet {
+		verbose = false
+	}
 
-	// If verbose mode is on, print the command we're about to run
-	if verbose {
-		fmt.Printf("$ %s\n", command)
+	// Validate the timeout signal before running anything
+	var timeoutSignal os.Signal = syscall.SIGKILL
+	if opts.TimeoutSignal != "" {
+		sig, err := parseSignal(opts.TimeoutSignal)
+		if err != nil {
+			return -1, err
+		}
+		timeoutSignal = sig
+	}
+
+	// Use the global timeout if none was given
+	timeout := opts.Timeout
+	if timeout == 0 {
+		cfg, err := config.Get()
+		if err == nil && cfg.Settings.Timeout > 0 {
+			timeout = time.Duration(cfg.Settings.Timeout) * time.Second
+		}
+	}
+
+	// Resolve the working directory before doing anything else,
+	// so a bad path fails clearly instead of inside the shell
+	workingDir := ""
+	if opts.WorkingDir != "" {
+		dir, err := ResolveWorkingDir(opts.WorkingDir)
+		if err != nil {
+			return -1, err
+		}
+		workingDir = dir
+	}
+
+	// Use the terminal for any streams that weren't given
+	stdin, stdout, stderr := opts.Stdin, opts.Stdout, opts.Stderr
+	if stdin == nil {
+		stdin = os.Stdin
+	}
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+
+	// The command as it's shown to the user
+	display := command
+	if opts.DisplayCommand != "" {
+		display = opts.DisplayCommand
 	}
 
 	// If dry run, just return without executing
 	if opts.DryRun {
-		fmt.Printf("[dry-run] Would execute: %s\n", command)
+		if opts.Raw {
+			// Unmasked on purpose; see ExecuteOptions.Raw
+			fmt.Fprintln(stdout, command)
+			return 0, nil
+		}
+		if verbose {
+			printVerbose(stdout, stderr, display)
+		}
+		fmt.Fprintf(stdout, "[dry-run] Would execute: %s\n", display)
 		return 0, nil
 	}
 
-	// Create the command based on the operating system
-	var cmd *exec.Cmd
-	if runtime.GOOS == "windows" {
-		// On Windows, use cmd.exe with /C flag
-		// /C means "run this command and then terminate"
-		cmd = exec.Command("cmd", "/C", command)
-	} else {
-		// On Unix-like systems (macOS, Linux), use the shell with -c flag
-		// -c means "run the following string as a command"
-		cmd = exec.Command(shell, "-c", command)
+	// If verbose mode is on, print the command we're about to run
+	if verbose {
+		printVerbose(stdout, stderr, display)
+	}
+
+	// Set up a deadline if there's a timeout; otherwise the context
+	// only ends if the caller's does, and the command runs until it exits
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// Windows line endings would leave a \r at the end of every line,
+	// which the shell treats as part of the last word
+	command = strings.ReplaceAll(command, "\r\n", "\n")
+
+	// Create the command based on the operating system,
+	// or run it under its own interpreter if it starts with a shebang
+	name, args := buildShellArgs(shell, command, opts.LoginShell)
+	if interpreter, ok := ParseShebang(command); ok {
+		scriptPath, err := writeScriptFile(command, "")
+		if err != nil {
+			return -1, err
+		}
+		defer os.Remove(scriptPath)
+
+		name = interpreter[0]
+		args = append(interpreter[1:], scriptPath)
+	} else if runtime.GOOS == "windows" && name == "cmd" && strings.Contains(strings.TrimRight(command, "\n"), "\n") {
+		// cmd /C stops at the first newline, so multi-line commands
+		// run from a batch file instead
+		scriptPath, err := writeScriptFile(batchScript(command), ".cmd")
+		if err != nil {
+			return -1, err
+		}
+		defer os.Remove(scriptPath)
+
+		args = []string{"/C", scriptPath}
+	}
+	cmd := exec.CommandContext(ctx, name, args...)
+
+	// exited is closed once the command has finished,
+	// which cancels any pending grace period kill
+	exited := make(chan struct{})
+
+	// Only commands that can be stopped early need their own group
+	stoppable := timeout > 0 || opts.Context != nil
+
+	if stoppable {
+		// Run the command in its own process group, so that on timeout
+		// we kill everything it spawned, not just the shell
+		setProcessGroup(cmd)
+		cmd.Cancel = func() error {
+			return stopProcess(cmd, timeoutSignal, opts.TimeoutGrace, exited)
+		}
 	}
 
 	// Connect the command's input/output to our terminal
+	// (unless other streams were given in the options)
 	// This allows the command to:
 	// - Read input from the user (stdin)
 	// - Print output to the terminal (stdout)
 	// - Print errors to the terminal (stderr)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	// Run in the alias's working directory if it has one
+	cmd.Dir = workingDir
 
 	// Also inherit the environment variables from the current process
 	// This ensures commands can access things like PATH, HOME, etc.
-	cmd.Env = os.Environ()
+	// exec.Cmd uses the last value for duplicate keys, so the alias's
+	// own variables override inherited ones.
+	cmd.Env = append(os.Environ(), opts.Env...)
 
 	// Run the command and wait for it to complete
-	err := cmd.Run()
+	var err error
+	if stoppable {
+		err = runForwardingInterrupts(cmd)
+	} else {
+		err = cmd.Run()
+	}
+	close(exited)
+
+	// A command killed by the deadline reports a timeout rather
+	// than the exit code from the kill signal
+	if ctx.Err() == context.DeadlineExceeded {
+		return -1, &TimeoutError{Timeout: timeout}
+	}
 
 	// Extract the exit code from the result
 	// A nil error means the command succeeded (exit code 0)
@@ -107,31 +327,380 @@ func Execute(command string, opts ExecuteOptions) (int, error) {
 	}
 
 	// If we couldn't start the command at all, return the error
-	return -1, fmt.Errorf("failed to execute command: %w", err)
+	return -1, &StartError{Err: err}
+}
+
+// printVerbose prints the command about to run, along with the config
+// files in effect (the global one, and the project one if there is one).
+// The config paths go to stderr so they don't mix with the command's
+// output, and help explain which aliases are being used.
+func printVerbose(stdout, stderr io.Writer, command string) {
+	fmt.Fprintf(stderr, "# config: %s\n", config.GetConfigFilePath())
+	if path := config.ProjectConfigPath(); path != "" {
+		fmt.Fprintf(stderr, "# project config: %s\n", path)
+	}
+	fmt.Fprintf(stdout, "$ %s\n", command)
+}
+
+// runForwardingInterrupts runs a command that is in its own process
+// group. Ctrl+C from the terminal only reaches aliasly's process group,
+// so it is forwarded to the command's group while it runs.
+func runForwardingInterrupts(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		for {
+			select {
+			case sig := <-interrupts:
+				signalProcessGroup(cmd, sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return cmd.Wait()
+}
+
+// stopProcess is called when a command's timeout fires.
+// With SIGKILL it kills the command's process group immediately.
+// Otherwise it sends sig so the command can clean up, and kills the
+// group if it's still running after the grace period.
+func stopProcess(cmd *exec.Cmd, sig os.Signal, grace time.Duration, exited <-chan struct{}) error {
+	if sig == syscall.SIGKILL {
+		return killProcessGroup(cmd)
+	}
+
+	go func() {
+		select {
+		case <-time.After(grace):
+			killProcessGroup(cmd)
+		case <-exited:
+		}
+	}()
+
+	return signalProcessGroup(cmd, sig)
+}
+
+// parseSignal converts a signal name like "SIGTERM" or "term" to a signal.
+func parseSignal(name string) (os.Signal, error) {
+	upper := strings.ToUpper(strings.TrimSpace(name))
+	if !strings.HasPrefix(upper, "SIG") {
+		upper = "SIG" + upper
+	}
+
+	switch upper {
+	case "SIGTERM":
+		return syscall.SIGTERM, nil
+	case "SIGINT":
+		return syscall.SIGINT, nil
+	case "SIGHUP":
+		return syscall.SIGHUP, nil
+	case "SIGQUIT":
+		return syscall.SIGQUIT, nil
+	case "SIGKILL":
+		return syscall.SIGKILL, nil
+	default:
+		return nil, fmt.Errorf("unsupported timeout signal: %s (use SIGTERM, SIGINT, SIGHUP, SIGQUIT or SIGKILL)", name)
+	}
+}
+
+// ResolveShell returns the shell commands run in: shell if it isn't
+// empty, otherwise the shell from the settings, otherwise the system
+// default.
+func ResolveShell(shell string) string {
+	if shell != "" {
+		return shell
+	}
+
+	// Try to get shell from config
+	if cfg, err := config.Get(); err == nil && cfg.Settings.Shell != "" {
+		return cfg.Settings.Shell
+	}
+
+	// Fall back to system default
+	return config.GetDefaultShell()
+}
+
+// ResolveWorkingDir expands ~ and environment variables in dir and
+// checks that it exists and is a directory.
+func ResolveWorkingDir(dir string) (string, error) {
+	expanded := os.ExpandEnv(dir)
+
+	if expanded == "~" || strings.HasPrefix(expanded, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to expand ~ in working directory: %w", err)
+		}
+		expanded = filepath.Join(home, expanded[1:])
+	}
+
+	info, err := os.Stat(expanded)
+	if err != nil {
+		return "", fmt.Errorf("working directory %s does not exist", expanded)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("working directory %s is not a directory", expanded)
+	}
+
+	return expanded, nil
+}
+
+// buildShellArgs returns the program and arguments used to run command.
+//
+// The whole command is passed as a single argument, so multi-line
+// commands (several statements, here-docs, lines joined with &&) run
+// as one script, exactly as if they were in a file.
+//
+// Commands always run in a fresh subshell, so they can't change the
+// caller's directory or environment. By default the shell is started
+// as a non-login shell for speed, which means functions defined in the
+// user's profile aren't available. Passing login adds the -l flag so
+// the profile is sourced first.
+func buildShellArgs(shell, command string, login bool) (string, []string) {
+	if runtime.GOOS == "windows" {
+		// PowerShell takes the command with -Command. Like a login
+		// shell on Unix, the user's profile is only loaded if asked for.
+		if isPowerShell(shell) {
+			args := []string{"-NoLogo", "-NoProfile", "-Command", powerShellCommand(command)}
+			if login {
+				args = []string{"-NoLogo", "-Command", powerShellCommand(command)}
+			}
+			return shell, args
+		}
+
+		// Otherwise use cmd.exe with /C flag
+		// /C means "run this command and then terminate"
+		return "cmd", []string{"/C", command}
+	}
+
+	// On Unix-like systems (macOS, Linux), use the shell with -c flag
+	// -c means "run the following string as a command"
+	if login && hasLoginFlag(shell) {
+		return shell, []string{"-l", "-c", command}
+	}
+	return shell, []string{"-c", command}
+}
+
+// hasLoginFlag reports whether shell, given as a name or a path,
+// understands -l. POSIX sh doesn't define it, so plain sh runs the
+// command without loading a profile.
+func hasLoginFlag(shell string) bool {
+	switch filepath.Base(shell) {
+	case "bash", "zsh", "ksh", "fish":
+		return true
+	}
+	return false
+}
+
+// isPowerShell reports whether shell is Windows PowerShell or
+// PowerShell 7+, given as a name or a path, with or without .exe.
+func isPowerShell(shell string) bool {
+	base := shellBaseName(shell)
+	return base == "powershell" || base == "pwsh"
+}
+
+// shellBaseName returns the lowercase name of shell, given as a name
+// or a path, without a .exe suffix.
+func shellBaseName(shell string) string {
+	return strings.TrimSuffix(strings.ToLower(filepath.Base(shell)), ".exe")
+}
+
+// powerShellCommand adds a line to command that makes PowerShell exit
+// with the command's exit code. On its own, -Command exits with 1 for
+// any failure, hiding the real exit code of a failed native program.
+func powerShellCommand(command string) string {
+	return strings.TrimRight(command, "\r\n") + "\nif (-not $?) { if ($LASTEXITCODE) { exit $LASTEXITCODE }; exit 1 }"
+}
+
+// checkRoot returns an error if aliasly is running as root (or as
+// administrator on Windows) and the alias, or the warn_on_root setting,
+// says it shouldn't run that way.
+func checkRoot(a Alias) error {
+	warnOnRoot := false
+	if cfg, err := config.Get(); err == nil {
+		warnOnRoot = cfg.Settings.WarnOnRoot
+	}
+
+	if !a.RefuseRoot && !warnOnRoot {
+		return nil
+	}
+	if !isElevated() {
+		return nil
+	}
+
+	if a.RefuseRoot {
+		return fmt.Errorf("alias '%s' refuses to run as root; pass --yes to run it anyway", a.Name)
+	}
+	return fmt.Errorf("running as root (warn_on_root is on); pass --yes to run '%s' anyway", a.Name)
+}
+
+// MatchConfirmPattern returns the first of patterns found in command,
+// ignoring case, or "" if none match. Empty patterns never match.
+func MatchConfirmPattern(command string, patterns []string) string {
+	lower := strings.ToLower(command)
+	for _, pattern := range patterns {
+		if pattern != "" && strings.Contains(lower, strings.ToLower(pattern)) {
+			return pattern
+		}
+	}
+	return ""
+}
+
+// ParseShebang returns the interpreter and its arguments if command
+// starts with a shebang line like "#!/usr/bin/env python".
+// Commands without a shebang return ok false and run in the shell.
+func ParseShebang(command string) ([]string, bool) {
+	if !strings.HasPrefix(command, "#!") {
+		return nil, false
+	}
+
+	firstLine, _, _ := strings.Cut(command, "\n")
+	interpreter := strings.Fields(strings.TrimSuffix(firstLine[2:], "\r"))
+	if len(interpreter) == 0 {
+		return nil, false
+	}
+
+	return interpreter, true
+}
+
+// writeScriptFile writes a command to a temporary file so it can be
+// passed to its interpreter, using ext as the file extension.
+// For shebang commands the shebang line is kept, since the common
+// interpreters (python, ruby, node, perl, sh) treat it as a comment.
+// The caller is responsible for removing the file.
+func writeScriptFile(command, ext string) (string, error) {
+	f, err := os.CreateTemp("", "aliasly-script-*"+ext)
+	if err != nil {
+		return "", fmt.Errorf("failed to create script file: %w", err)
+	}
+
+	if _, err := f.WriteString(command); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write script file: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write script file: %w", err)
+	}
+
+	return f.Name(), nil
+}
+
+// batchScript turns a multi-line command into a batch file for cmd.exe.
+// Echo is turned off so the lines aren't printed as they run, and
+// lines end in \r\n as cmd expects.
+func batchScript(command string) string {
+	return "@echo off\r\n" + strings.ReplaceAll(command, "\n", "\r\n")
 }
 
 // Run is a convenience function that parses an alias with arguments
 // and executes the resulting command.
 // This is the main entry point for running aliases.
 func Run(a Alias, args []string) (int, error) {
+	return RunWithOptions(a, args, ExecuteOptions{})
+}
+
+// RunWithOptions is like Run but allows specifying execution options.
+func RunWithOptions(a Alias, args []string, opts ExecuteOptions) (int, error) {
 	// Parse the command by substituting parameters
 	command, err := ParseCommand(a, args)
 	if err != nil {
 		return -1, err
 	}
 
-	// Execute the parsed command
-	return Execute(command, ExecuteOptions{})
-}
+	// Secret values are masked wherever the command is shown or recorded
+	masked, err := ParseCommandMasked(a, args)
+	if err != nil {
+		return -1, err
+	}
+	opts.DisplayCommand = masked
 
-// RunWithOptions is like Run but allows specifying execution options.
-func RunWithOptions(a Alias, args []string, opts ExecuteOptions) (int, error) {
-	// Parse the command by substituting parameters
-	command, err := ParseCommand(a, args)
+	// Substitute parameters into the alias's environment variables
+	env, err := ParseEnv(a, args)
 	if err != nil {
 		return -1, err
 	}
+	opts.Env = append(opts.Env, env...)
+
+	// Use the alias's timeout unless one was given explicitly
+	if opts.Timeout == 0 && a.Timeout > 0 {
+		opts.Timeout = time.Duration(a.Timeout) * time.Second
+	}
+
+	// Use the alias's timeout signal settings unless given explicitly
+	if opts.TimeoutSignal == "" {
+		opts.TimeoutSignal = a.TimeoutSignal
+	}
+	if opts.TimeoutGrace == 0 && a.TimeoutGrace > 0 {
+		opts.TimeoutGrace = time.Duration(a.TimeoutGrace) * time.Second
+	}
+
+	// Use the alias's working directory unless one was given explicitly
+	if opts.WorkingDir == "" {
+		opts.WorkingDir = a.WorkingDir
+	}
+
+	// Guard against running powerful aliases with sudo by mistake.
+	// Dry runs are always allowed since nothing is executed.
+	if !opts.DryRun && !opts.AllowRoot {
+		if err := checkRoot(a); err != nil {
+			return -1, err
+		}
+	}
+
+	// Commands matching a confirm pattern need an explicit yes
+	if !opts.DryRun && !opts.SkipConfirm {
+		if cfg, err := config.Get(); err == nil {
+			if pattern := MatchConfirmPattern(command, cfg.Settings.ConfirmPatterns); pattern != "" {
+				if opts.Confirm == nil {
+					return -1, fmt.Errorf("alias '%s' runs a command matching confirm pattern '%s' and needs confirmation", a.Name, pattern)
+				}
+				if !opts.Confirm(masked, pattern) {
+					return -1, ErrCancelled
+				}
+			}
+		}
+	}
+
+	// Count the run for 'al stats'. A failure here shouldn't stop
+	// the alias from running, so the error is ignored.
+	if !opts.DryRun {
+		if cfg, err := config.Get(); err == nil && cfg.Settings.UsageTrackingEnabled() {
+			config.IncrementUsage(a.Name)
+		}
+	}
 
 	// Execute the parsed command with the given options
-	return Execute(command, opts)
+	started := time.Now()
+	exitCode, err := Execute(command, opts)
+
+	// Record the run for 'al history' if it's turned on. As with
+	// stats, a failure to record shouldn't affect the run.
+	if !opts.DryRun {
+		if cfg, cfgErr := config.Get(); cfgErr == nil && cfg.Settings.History {
+			// An unknown hostname just leaves the entry without one
+			host, _ := os.Hostname()
+			config.AppendHistory(config.HistoryEntry{
+				Time:     started,
+				Alias:    a.Name,
+				Command:  masked,
+				ExitCode: exitCode,
+				Host:     host,
+			}, cfg.Settings.HistoryLimit())
+		}
+	}
+
+	return exitCode, err
 }
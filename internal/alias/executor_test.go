@@ -0,0 +1,110 @@
+package alias
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"aliasly/internal/config"
+)
+
+// useTempConfig points aliasly at an empty config in a temporary
+// directory, so tests don't read or change the user's own.
+func useTempConfig(t *testing.T) {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("ALIASLY_CONFIG_DIR", filepath.Join(dir, "config"))
+	t.Setenv("ALIASLY_STATE_DIR", filepath.Join(dir, "state"))
+	t.Setenv("ALIASLY_NO_DEFAULTS", "1")
+	if err := config.Load(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildShellArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		goos     string
+		shell    string
+		login    bool
+		wantName string
+		wantArgs []string
+	}{
+		{"bash", "unix", "/bin/bash", false, "/bin/bash", []string{"-c", "echo hi"}},
+		{"bash login", "unix", "/bin/bash", true, "/bin/bash", []string{"-l", "-c", "echo hi"}},
+		{"zsh login", "unix", "/usr/bin/zsh", true, "/usr/bin/zsh", []string{"-l", "-c", "echo hi"}},
+		{"sh login", "unix", "/bin/sh", true, "/bin/sh", []string{"-c", "echo hi"}},
+		{"cmd login", "windows", "cmd", true, "cmd", []string{"/C", "echo hi"}},
+		{"powershell", "windows", "pwsh", false, "pwsh", []string{"-NoLogo", "-NoProfile", "-Command", powerShellCommand("echo hi")}},
+		{"powershell login", "windows", "pwsh", true, "pwsh", []string{"-NoLogo", "-Command", powerShellCommand("echo hi")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if (tt.goos == "windows") != (runtime.GOOS == "windows") {
+				t.Skipf("only applies on %s", tt.goos)
+			}
+
+			name, args := buildShellArgs(tt.shell, "echo hi", tt.login)
+			if name != tt.wantName || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("buildShellArgs(%q, login=%v) = %q %q, want %q %q",
+					tt.shell, tt.login, name, args, tt.wantName, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestExecuteMultiLine(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell syntax")
+	}
+	useTempConfig(t)
+
+	tests := []struct {
+		name     string
+		command  string
+		wantOut  string
+		wantCode int
+	}{
+		{
+			name:    "variable set on one line is seen on the next",
+			command: "greeting=hello\necho \"$greeting world\"\n",
+			wantOut: "hello world\n",
+		},
+		{
+			name:    "here-doc",
+			command: "cat <<EOF\nline one\nline two\nEOF",
+			wantOut: "line one\nline two\n",
+		},
+		{
+			name:     "set -e stops at the failing line",
+			command:  "set -e\necho before\nfalse\necho after",
+			wantOut:  "before\n",
+			wantCode: 1,
+		},
+		{
+			name:    "without set -e later lines still run",
+			command: "false\necho after",
+			wantOut: "after\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			code, err := Execute(tt.command, ExecuteOptions{Shell: "/bin/sh", Stdout: &stdout, Stderr: &bytes.Buffer{}})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+			}
+			if got := stdout.String(); got != tt.wantOut {
+				t.Errorf("output = %q, want %q", got, tt.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package alias
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"aliasly/internal/config"
+)
+
+// NamePattern returns the regular expression (without anchors) that
+// alias names must match under the given policy.
+// The web UI uses it for the name input's pattern attribute.
+func NamePattern(policy config.NamePolicy) string {
+	extra := ""
+	if policy.AllowDot {
+		extra += `.`
+	}
+	if policy.AllowColon {
+		extra += `:`
+	}
+
+	// Inside a character class, "." and ":" are literal, and the hyphen
+	// is kept last so it isn't treated as a range
+	return `[a-zA-Z][a-zA-Z0-9` + extra + `-]*`
+}
+
+// NameHint returns a human-readable description of the naming rules.
+func NameHint(policy config.NamePolicy) string {
+	allowed := []string{"letters", "numbers", "hyphens"}
+	if policy.AllowDot {
+		allowed = append(allowed, "dots")
+	}
+	if policy.AllowColon {
+		allowed = append(allowed, "colons")
+	}
+
+	last := len(allowed) - 1
+	hint := fmt.Sprintf("%s, and %s only. Must start with a letter.",
+		strings.Join(allowed[:last], ", "), allowed[last])
+	hint = strings.ToUpper(hint[:1]) + hint[1:]
+
+	if policy.MaxLength > 0 {
+		hint += fmt.Sprintf(" At most %d characters.", policy.MaxLength)
+	}
+
+	return hint
+}
+
+// ValidateName checks that name follows the configured name policy.
+// Returns an error describing the rules if it doesn't.
+func ValidateName(name string) error {
+	var policy config.NamePolicy
+	if cfg, err := config.Get(); err == nil {
+		policy = cfg.Settings.NamePolicy
+	}
+
+	return ValidateNameWithPolicy(name, policy)
+}
+
+// ValidateNameWithPolicy checks that name follows the given name policy,
+// for checking names from a config other than the loaded one.
+func ValidateNameWithPolicy(name string, policy config.NamePolicy) error {
+	pattern := regexp.MustCompile(`^` + NamePattern(policy) + `$`)
+	if !pattern.MatchString(name) {
+		return fmt.Errorf("invalid name '%s': %s", name, NameHint(policy))
+	}
+
+	if policy.MaxLength > 0 && len(name) > policy.MaxLength {
+		return fmt.Errorf("name '%s' is too long (max %d characters)", name, policy.MaxLength)
+	}
+
+	return nil
+}
+
+// ShadowedCommand looks up name in $PATH and returns the path of the
+// command an alias with that name would share its name with.
+// An alias named like a real command works through 'al', but can be
+// confusing if the shell also defines it as an alias or function.
+func ShadowedCommand(name string) (string, bool) {
+	if name == "" {
+		return "", false
+	}
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", false
+	}
+	return path, true
+}
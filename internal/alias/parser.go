@@ -2,8 +2,15 @@ package alias
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+
+	"aliasly/internal/config"
 )
 
 // paramPattern is a regular expression that matches {{paramName}} placeholders.
@@ -11,6 +18,9 @@ import (
 // For example, it will match: {{message}}, {{branch}}, {{version_number}}
 var paramPattern = regexp.MustCompile(`\{\{(\w+)\}\}`)
 
+// aliasRefPattern matches {{alias:name}} references to other aliases.
+var aliasRefPattern = regexp.MustCompile(`\{\{alias:([^{}\s]+)\}\}`)
+
 // ParseError represents an error that occurred during command parsing.
 // It provides detailed information about what went wrong.
 type ParseError struct {
@@ -35,46 +45,704 @@ func (e *ParseError) Error() string {
 //   Args: ["fix bug"]
 //   Result: git commit -am "fix bug"
 //
+// Arguments can also name the parameter they're for, using either
+// name=value or --name value (--name=value also works). Named and
+// positional arguments can be mixed; see matchArgs for details.
+//
+// The command can also reference other aliases with {{alias:name}};
+// see expandAliasRefs for details.
+//
+// Parts of the command wrapped in [[ ]] are only included when their
+// parameters have values; see expandOptionalSegments for details.
+//
 // Returns an error if required parameters are missing.
 func ParseCommand(a Alias, args []string) (string, error) {
+	return parseCommand(a, args, []string{a.Name}, false)
+}
+
+// SecretMask replaces the values of secret parameters in commands
+// that are shown rather than run.
+const SecretMask = "****"
+
+// ParseCommandMasked is like ParseCommand, but substitutes SecretMask
+// for the values of secret parameters (including those of referenced
+// aliases). Use it for any command that is displayed or logged.
+func ParseCommandMasked(a Alias, args []string) (string, error) {
+	return parseCommand(a, args, []string{a.Name}, true)
+}
+
+// parseCommand is ParseCommand with the chain of aliases currently
+// being expanded, used to detect reference cycles. If mask is true,
+// the values of secret parameters are replaced by SecretMask.
+func parseCommand(a Alias, args []string, chain []string, mask bool) (string, error) {
+	values, err := ResolveParams(a, args)
+	if err != nil {
+		return "", err
+	}
+
+	// Drop optional segments whose params are empty. This is done on the
+	// alias's own command, before referenced aliases are spliced in.
+	command, err := expandOptionalSegments(a, values)
+	if err != nil {
+		return "", err
+	}
+
+	// Splice in referenced aliases before substituting parameters,
+	// so argument values can't inject {{alias:...}} references
+	command, err = expandAliasRefs(command, chain, mask)
+	if err != nil {
+		return "", err
+	}
+
+	// Check whether all values should be quoted
+	quoteAll := false
+	if cfg, err := config.Get(); err == nil {
+		quoteAll = cfg.Settings.QuoteParams
+	}
+
+	// Substitute each parameter placeholder with its value
+	for _, param := range a.Params {
+		placeholder := fmt.Sprintf("{{%s}}", param.Name)
+		value := values[param.Name]
+
+		// Empty values are left as-is so optional params can still disappear
+		if mask && param.Secret && value != "" {
+			value = SecretMask
+		} else if (quoteAll || param.Quote) && value != "" {
+			value = ShellQuote(value)
+		}
+
+		// Replace all occurrences of the placeholder with the value
+		command = strings.ReplaceAll(command, placeholder, value)
+	}
+
+	return command, nil
+}
+
+// expandOptionalSegments handles the optional segments of an alias's
+// command. A segment is text wrapped in [[ and ]] that contains at least
+// one {{param}} placeholder, like [[ -m "{{msg}}" ]]. If every param in
+// it has a value, the brackets are removed and the text is kept;
+// otherwise the whole segment is dropped.
+//
+// Brackets without a placeholder of the alias are left alone, so shell
+// tests like [[ -f file ]] still work. Segments can't be nested.
+func expandOptionalSegments(a Alias, values map[string]string) (string, error) {
 	command := a.Command
+	var result strings.Builder
+
+	for {
+		start := strings.Index(command, "[[")
+		if start == -1 {
+			break
+		}
+		end := strings.Index(command[start+2:], "]]")
+		if end == -1 {
+			break
+		}
+		end += start + 2
+
+		inner := command[start+2 : end]
+		result.WriteString(command[:start])
+		command = command[end+2:]
+
+		// Only treat it as a segment if it uses one of the alias's params
+		params := make([]string, 0)
+		for _, name := range ExtractPlaceholders(inner) {
+			if _, declared := values[name]; declared {
+				params = append(params, name)
+			}
+		}
+		if len(params) == 0 {
+			result.WriteString("[[" + inner + "]]")
+			continue
+		}
+
+		if strings.Contains(inner, "[[") {
+			return "", fmt.Errorf("alias '%s': nested optional segments [[ ]] are not supported", a.Name)
+		}
+
+		include := true
+		for _, name := range params {
+			if values[name] == "" {
+				include = false
+				break
+			}
+		}
+		if include {
+			result.WriteString(strings.TrimSpace(inner))
+		}
+	}
+
+	result.WriteString(command)
+	return result.String(), nil
+}
+
+// HasOptionalSegments reports whether the alias's command has any
+// optional [[ ]] segments (see expandOptionalSegments).
+func HasOptionalSegments(a Alias) bool {
+	// With every param set, segments are kept without their brackets,
+	// so the command only changes if there are any
+	values := make(map[string]string, len(a.Params))
+	for _, p := range a.Params {
+		values[p.Name] = "x"
+	}
+
+	expanded, err := expandOptionalSegments(a, values)
+	return err != nil || expanded != a.Command
+}
+
+// expandAliasRefs replaces every {{alias:name}} in command with the
+// fully expanded command of the named alias. Referenced aliases are
+// expanded recursively, without arguments, so their optional params
+// use defaults. A reference cycle (a -> b -> a) is an error.
+func expandAliasRefs(command string, chain []string, mask bool) (string, error) {
+	var expandErr error
+
+	expanded := aliasRefPattern.ReplaceAllStringFunc(command, func(ref string) string {
+		if expandErr != nil {
+			return ref
+		}
+
+		name := aliasRefPattern.FindStringSubmatch(ref)[1]
+
+		// Detect cycles by checking whether we're already expanding it
+		for _, seen := range chain {
+			if seen == name {
+				cycle := append(append([]string{}, chain...), name)
+				expandErr = fmt.Errorf("alias reference cycle: %s", strings.Join(cycle, " -> "))
+				return ref
+			}
+		}
+
+		target, found := config.FindAlias(name)
+		if !found {
+			expandErr = fmt.Errorf("referenced alias '%s' not found", name)
+			return ref
+		}
+
+		inner, err := parseCommand(target, nil, append(chain, name), mask)
+		if err != nil {
+			expandErr = err
+
+			// Wrap parse errors so a missing param in the referenced alias
+			// isn't reported as a usage error of the outer alias
+			if _, ok := err.(*ParseError); ok {
+				expandErr = fmt.Errorf("referenced alias '%s': %w", name, err)
+			}
+			return ref
+		}
+
+		return inner
+	})
+
+	if expandErr != nil {
+		return "", expandErr
+	}
+	return expanded, nil
+}
+
+// ParseEnv returns the alias's environment variables as KEY=value
+// pairs, with {{param}} placeholders in the values substituted.
+// Values aren't shell-quoted since they never pass through the shell.
+// The pairs are sorted by key so the result is deterministic.
+func ParseEnv(a Alias, args []string) ([]string, error) {
+	return parseEnv(a, args, false)
+}
+
+// ParseEnvMasked is like ParseEnv, but substitutes SecretMask for the
+// values of secret parameters, for environments that are displayed.
+func ParseEnvMasked(a Alias, args []string) ([]string, error) {
+	return parseEnv(a, args, true)
+}
+
+// parseEnv is ParseEnv, masking secret values if mask is true.
+func parseEnv(a Alias, args []string, mask bool) ([]string, error) {
+	if len(a.Env) == 0 {
+		return nil, nil
+	}
+
+	values, err := ResolveParams(a, args)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(a.Env))
+	for key := range a.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	env := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value := a.Env[key]
+		for _, param := range a.Params {
+			paramValue := values[param.Name]
+			if mask && param.Secret && paramValue != "" {
+				paramValue = SecretMask
+			}
+			value = strings.ReplaceAll(value, "{{"+param.Name+"}}", paramValue)
+		}
+		env = append(env, key+"="+value)
+	}
+
+	return env, nil
+}
+
+// ResolveParams assigns the arguments to the alias's parameters and
+// returns the value for every parameter.
+//
+// A parameter that wasn't given as an argument is read from its
+// environment variable (if it has one and it's set), then falls back
+// to its default. Required parameters with no value are an error.
+// Returns an error if required parameters are missing or a value
+// doesn't satisfy its parameter's constraints.
+func ResolveParams(a Alias, args []string) (map[string]string, error) {
+	if err := ValidateVariadic(a); err != nil {
+		return nil, err
+	}
+	if err := ValidateConstraints(a); err != nil {
+		return nil, err
+	}
 
 	// Build a map of parameter name -> value from the provided arguments.
-	// Arguments are positional, so args[0] goes to the first param, etc.
-	provided := make(map[string]string)
-	for i, param := range a.Params {
-		if i < len(args) {
-			provided[param.Name] = args[i]
+	provided, err := matchArgs(a, args)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, param := range a.Params {
+		if _, hasValue := provided[param.Name]; hasValue {
+			continue
+		}
+
+		// Read the value from the environment if it's backed by a variable
+		if param.EnvVar != "" {
+			if value := os.Getenv(param.EnvVar); value != "" {
+				provided[param.Name] = value
+				continue
+			}
+		}
+
+		// Check that all required parameters are provided
+		if param.Required {
+			message := fmt.Sprintf("missing required parameter: %s", param.Name)
+			if param.EnvVar != "" {
+				message += fmt.Sprintf(" (or set %s)", param.EnvVar)
+			}
+			return nil, &ParseError{
+				Message:   message,
+				ParamName: param.Name,
+			}
+		}
+
+		// Use default value for optional parameters
+		provided[param.Name] = param.Default
+	}
+
+	// Empty optional values are allowed so params can still be left out
+	for _, param := range a.Params {
+		value := provided[param.Name]
+		if value == "" {
+			continue
+		}
+		if err := checkConstraints(param, value); err != nil {
+			return nil, err
 		}
+		if param.Type == config.ParamTypePath {
+			if provided[param.Name], err = resolvePath(param, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return provided, nil
+}
+
+// MissingParams returns the required parameters that get no value from
+// args or from their environment variables, in declaration order.
+// Returns an error if the arguments can't be assigned to parameters.
+func MissingParams(a Alias, args []string) ([]Param, error) {
+	provided, err := matchArgs(a, args)
+	if err != nil {
+		return nil, err
 	}
 
-	// Check that all required parameters are provided
+	var missing []Param
 	for _, param := range a.Params {
-		_, hasValue := provided[param.Name]
-		if param.Required && !hasValue {
+		if _, hasValue := provided[param.Name]; hasValue || !param.Required {
+			continue
+		}
+		if param.EnvVar != "" && os.Getenv(param.EnvVar) != "" {
+			continue
+		}
+		missing = append(missing, param)
+	}
+
+	return missing, nil
+}
+
+// CheckValue checks a value against the param's type, choices and
+// validation pattern, returning a *ParseError if it doesn't satisfy them.
+func CheckValue(param Param, value string) error {
+	if err := checkConstraints(param, value); err != nil {
+		return err
+	}
+	if param.Type == config.ParamTypePath {
+		_, err := resolvePath(param, value)
+		return err
+	}
+	return nil
+}
+
+// checkType checks that a value parses as the param's type.
+// Whether a path exists is checked separately by resolvePath, since
+// it depends on the machine rather than the value.
+func checkType(param Param, value string) error {
+	valid := true
+	switch param.Type {
+	case config.ParamTypeInt:
+		_, err := strconv.ParseInt(value, 10, 64)
+		valid = err == nil
+	case config.ParamTypeBool:
+		valid = value == "true" || value == "false"
+	}
+	if valid {
+		return nil
+	}
+
+	expected := "an integer"
+	if param.Type == config.ParamTypeBool {
+		expected = "true or false"
+	}
+	return &ParseError{
+		Message:   fmt.Sprintf("invalid value '%s' for parameter %s: must be %s", value, param.Name, expected),
+		ParamName: param.Name,
+	}
+}
+
+// resolvePath expands a leading ~ in the value of a path parameter
+// and, if the param has must_exist, checks that the path exists.
+func resolvePath(param Param, value string) (string, error) {
+	if value == "~" || strings.HasPrefix(value, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to expand ~ in parameter %s: %w", param.Name, err)
+		}
+		value = filepath.Join(home, value[1:])
+	}
+
+	if param.MustExist {
+		if _, err := os.Stat(value); err != nil {
 			return "", &ParseError{
-				Message:   fmt.Sprintf("missing required parameter: %s", param.Name),
+				Message:   fmt.Sprintf("invalid value for parameter %s: path %s does not exist", param.Name, value),
 				ParamName: param.Name,
 			}
 		}
 	}
 
-	// Substitute each parameter placeholder with its value
+	return value, nil
+}
+
+// checkConstraints checks a value against the param's type, choices
+// and validation pattern. The pattern must match the whole value.
+func checkConstraints(param Param, value string) error {
+	if err := checkType(param, value); err != nil {
+		return err
+	}
+
+	if len(param.Choices) > 0 {
+		allowed := false
+		for _, choice := range param.Choices {
+			if value == choice {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return &ParseError{
+				Message:   fmt.Sprintf("invalid value '%s' for parameter %s: must be one of %s", value, param.Name, strings.Join(param.Choices, ", ")),
+				ParamName: param.Name,
+			}
+		}
+	}
+
+	if param.Validate != "" {
+		// ValidateConstraints has already checked that the pattern compiles
+		pattern := regexp.MustCompile(`^(?:` + param.Validate + `)$`)
+		if !pattern.MatchString(value) {
+			return &ParseError{
+				Message:   fmt.Sprintf("invalid value '%s' for parameter %s: must match %s", value, param.Name, param.Validate),
+				ParamName: param.Name,
+			}
+		}
+	}
+
+	return nil
+}
+
+// matchArgs assigns arguments to the alias's parameters.
+//
+// Arguments of the form name=value, --name=value, --name value, or
+// -p name=value (also --param name=value) are assigned to the parameter
+// with that name. Only the first "=" is used
+// to split, so values may themselves contain "=". Tokens that don't
+// name a declared parameter are treated as positional, so something
+// like FOO=bar is still passed through when there's no "FOO" param.
+//
+// The remaining positional arguments fill the parameters that weren't
+// named; see positionalTargets for which ones. If the last parameter is
+// variadic, it takes all positional arguments left over, joined by spaces.
+//
+// Any positional arguments still left over are ignored, unless
+// settings.strict_args is on, in which case they're a *ParseError.
+func matchArgs(a Alias, args []string) (map[string]string, error) {
+	provided, taken, extra, err := splitArgs(a, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(extra) > 0 {
+		if cfg, err := config.Get(); err == nil && cfg.Settings.StrictArgs {
+			return nil, &ParseError{
+				Message: fmt.Sprintf("too many arguments: expected at most %d, got %d (extra: %s); quote values that contain spaces",
+					taken, taken+len(extra), strings.Join(extra, " ")),
+			}
+		}
+	}
+
+	return provided, nil
+}
+
+// ExtraArgs returns the positional arguments that no parameter takes.
+// They're ignored when the alias runs, or an error with strict_args.
+func ExtraArgs(a Alias, args []string) ([]string, error) {
+	_, _, extra, err := splitArgs(a, args)
+	return extra, err
+}
+
+// splitArgs is matchArgs without the strict_args check. It also returns
+// how many positional arguments were taken by parameters, and the ones
+// left over.
+func splitArgs(a Alias, args []string) (map[string]string, int, []string, error) {
+	declared := make(map[string]bool, len(a.Params))
 	for _, param := range a.Params {
-		placeholder := fmt.Sprintf("{{%s}}", param.Name)
+		declared[param.Name] = true
+	}
 
-		// Get the value to substitute
-		value, hasValue := provided[param.Name]
-		if !hasValue {
-			// Use default value for optional parameters
-			value = param.Default
+	provided := make(map[string]string)
+	positional := make([]string, 0, len(args))
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if strings.HasPrefix(arg, "--") {
+			flag := strings.TrimPrefix(arg, "--")
+
+			// --name=value
+			if name, value, ok := strings.Cut(flag, "="); ok && declared[name] {
+				provided[name] = value
+				continue
+			}
+
+			// --name value
+			if declared[flag] {
+				if i+1 >= len(args) {
+					return nil, 0, nil, &ParseError{
+						Message:   fmt.Sprintf("missing value for parameter: %s", flag),
+						ParamName: flag,
+					}
+				}
+				provided[flag] = args[i+1]
+				i++
+				continue
+			}
+		} else if name, value, ok := strings.Cut(arg, "="); ok && declared[name] {
+			// name=value
+			provided[name] = value
+			continue
 		}
 
-		// Replace all occurrences of the placeholder with the value
-		command = strings.ReplaceAll(command, placeholder, value)
+		// -p name=value or --param name=value, like al's own --param flag,
+		// which also rejects names the alias doesn't declare
+		if (arg == "-p" || arg == "--param") && i+1 < len(args) {
+			if name, value, ok := strings.Cut(args[i+1], "="); ok && name != "" {
+				if !declared[name] {
+					return nil, 0, nil, &ParseError{
+						Message: fmt.Sprintf("alias '%s' has no parameter '%s'", a.Name, name),
+					}
+				}
+				provided[name] = value
+				i++
+				continue
+			}
+		}
+
+		positional = append(positional, arg)
 	}
 
-	return command, nil
+	// Fill the parameters that weren't named with positional args
+	var unnamed []Param
+	for _, param := range a.Params {
+		if _, named := provided[param.Name]; !named {
+			unnamed = append(unnamed, param)
+		}
+	}
+
+	targets, err := positionalTargets(unnamed, len(positional))
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	next := 0
+	for _, param := range targets {
+		if next >= len(positional) {
+			break
+		}
+		if param.Variadic {
+			provided[param.Name] = strings.Join(positional[next:], " ")
+			next = len(positional)
+			continue
+		}
+		provided[param.Name] = positional[next]
+		next++
+	}
+
+	return provided, next, positional[next:], nil
+}
+
+// positionalTargets returns the parameters that n positional arguments
+// fill, in order, given the parameters that weren't named.
+//
+// If there are at least as many arguments as parameters, or no optional
+// parameter comes before a required one, the parameters are filled in
+// declaration order and the ones left over keep their defaults.
+//
+// Otherwise an optional parameter sits between required ones, and
+// filling in order would shift the later arguments onto the wrong
+// parameters. So with at most as many arguments as required parameters,
+// only the required parameters are filled. With more than that, there
+// is no telling which optional parameters the extra arguments are for,
+// and a *ParseError asks for them to be passed by name.
+func positionalTargets(params []Param, n int) ([]Param, error) {
+	var required, optional []Param
+	interleaved := false
+	for _, param := range params {
+		if param.Required {
+			if len(optional) > 0 {
+				interleaved = true
+			}
+			required = append(required, param)
+		} else {
+			optional = append(optional, param)
+		}
+	}
+
+	if n >= len(params) || !interleaved {
+		return params, nil
+	}
+	if n <= len(required) {
+		return required, nil
+	}
+
+	names := make([]string, len(optional))
+	for i, param := range optional {
+		names[i] = param.Name
+	}
+	return nil, &ParseError{
+		Message: fmt.Sprintf("ambiguous arguments: %d values for %d required parameters and optional %s; pass the optional ones by name, e.g. %s=value",
+			n, len(required), strings.Join(names, ", "), names[0]),
+	}
+}
+
+// ShellQuote quotes a value so the shell commands run in (see
+// ResolveShell) treats it as a single literal argument, regardless of
+// spaces, quotes, or metacharacters. A --shell given for a single run
+// isn't known when commands are expanded, so the configured shell is used.
+func ShellQuote(value string) string {
+	return QuoteForShell(ResolveShell(""), value)
+}
+
+// QuoteForShell quotes a value for the given shell, named or given as a
+// path, so it is treated as a single literal argument.
+//
+// On POSIX shells the value is quoted with QuotePOSIX.
+// In fish, \' and \\ are escapes even inside single quotes, so
+// backslashes and single quotes are escaped with a backslash instead.
+// In PowerShell single quotes are literal too, and embedded single
+// quotes are doubled.
+// On Windows cmd the value is wrapped in double quotes with embedded
+// double quotes doubled. This protects against spaces and & | < >, but
+// cmd still expands %VAR% inside quotes and there is no way to escape it.
+func QuoteForShell(shell, value string) string {
+	switch {
+	case isPowerShell(shell):
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	case runtime.GOOS == "windows":
+		// Every other shell runs through cmd on Windows (see buildShellArgs)
+		return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+	case shellBaseName(shell) == "fish":
+		value = strings.ReplaceAll(value, `\`, `\\`)
+		return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+	}
+
+	return QuotePOSIX(value)
+}
+
+// QuotePOSIX wraps a value in single quotes, which disable all special
+// characters in POSIX shells; embedded single quotes become '\''.
+// Use it for output that is always read by a POSIX shell, whatever
+// shell aliasly itself is set up with.
+func QuotePOSIX(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// ValidateVariadic checks that only the last parameter of an alias
+// is marked as variadic.
+func ValidateVariadic(a Alias) error {
+	for i, param := range a.Params {
+		if param.Variadic && i != len(a.Params)-1 {
+			return &ParseError{
+				Message:   fmt.Sprintf("parameter '%s' is variadic but isn't the last parameter; only the last parameter can be variadic", param.Name),
+				ParamName: param.Name,
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateConstraints checks that every parameter has a known type and
+// its validation pattern is a valid regular expression, and that
+// defaults satisfy the parameter's constraints.
+func ValidateConstraints(a Alias) error {
+	for _, param := range a.Params {
+		switch param.Type {
+		case "", config.ParamTypeString, config.ParamTypeInt, config.ParamTypeBool, config.ParamTypePath:
+		default:
+			return fmt.Errorf("parameter '%s' has unknown type '%s'; must be string, int, bool or path", param.Name, param.Type)
+		}
+		if param.MustExist && param.Type != config.ParamTypePath {
+			return fmt.Errorf("parameter '%s' has must_exist, which only applies to path parameters", param.Name)
+		}
+
+		if param.Validate == "" {
+			continue
+		}
+		if _, err := regexp.Compile(param.Validate); err != nil {
+			return fmt.Errorf("parameter '%s' has an invalid validate pattern: %w", param.Name, err)
+		}
+	}
+
+	// A default that breaks the constraints would fail every run
+	for _, param := range a.Params {
+		if param.Default == "" {
+			continue
+		}
+		if err := checkConstraints(param, param.Default); err != nil {
+			return fmt.Errorf("default of parameter '%s' is invalid: %w", param.Name, err)
+		}
+	}
+	return nil
 }
 
 // ExtractPlaceholders finds all {{paramName}} placeholders in a command string.
@@ -121,6 +789,64 @@ func ValidatePlaceholders(a Alias) []string {
 	return undefined
 }
 
+// UndefinedPlaceholdersError is returned when an alias's command uses
+// placeholders that have no matching parameter definition.
+type UndefinedPlaceholdersError struct {
+	// Names are the undefined placeholders, without duplicates
+	Names []string
+}
+
+// Error implements the error interface for UndefinedPlaceholdersError.
+func (e *UndefinedPlaceholdersError) Error() string {
+	return fmt.Sprintf("command has undefined placeholders: %s", strings.Join(e.Names, ", "))
+}
+
+// CheckPlaceholders returns an *UndefinedPlaceholdersError if the
+// command uses placeholders without a matching parameter, so such an
+// alias is rejected when it's saved rather than when it's run.
+func CheckPlaceholders(a Alias) error {
+	undefined := ValidatePlaceholders(a)
+	if len(undefined) == 0 {
+		return nil
+	}
+
+	// A placeholder can appear more than once in the command
+	seen := make(map[string]bool)
+	names := make([]string, 0, len(undefined))
+	for _, name := range undefined {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	return &UndefinedPlaceholdersError{Names: names}
+}
+
+// UnusedParams returns the declared parameters that are never
+// referenced, neither in the command nor in an environment variable.
+// Unused parameters aren't an error, but are usually a mistake.
+func UnusedParams(a Alias) []string {
+	used := make(map[string]bool)
+	for _, name := range ExtractPlaceholders(a.Command) {
+		used[name] = true
+	}
+	for _, value := range a.Env {
+		for _, name := range ExtractPlaceholders(value) {
+			used[name] = true
+		}
+	}
+
+	unused := make([]string, 0)
+	for _, param := range a.Params {
+		if !used[param.Name] {
+			unused = append(unused, param.Name)
+		}
+	}
+
+	return unused
+}
+
 // FormatExample shows what a command would look like with example values.
 // This is useful for displaying help text to users.
 //
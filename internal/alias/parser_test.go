@@ -0,0 +1,35 @@
+package alias
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestQuoteForShell(t *testing.T) {
+	tests := []struct {
+		name  string
+		goos  string
+		shell string
+		value string
+		want  string
+	}{
+		{"posix", "unix", "/bin/bash", `it's $HOME`, `'it'\''s $HOME'`},
+		{"posix backslash", "unix", "/bin/sh", `a\b`, `'a\b'`},
+		{"fish", "unix", "/usr/bin/fish", `it's a\b`, `'it\'s a\\b'`},
+		{"powershell", "unix", "pwsh", `it's $HOME`, `'it''s $HOME'`},
+		{"windows powershell", "windows", `C:\Windows\powershell.exe`, `it's`, `'it''s'`},
+		{"windows cmd", "windows", "cmd", `say "hi"`, `"say ""hi"""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if (tt.goos == "windows") != (runtime.GOOS == "windows") {
+				t.Skipf("only applies on %s", tt.goos)
+			}
+
+			if got := QuoteForShell(tt.shell, tt.value); got != tt.want {
+				t.Errorf("QuoteForShell(%q, %q) = %s, want %s", tt.shell, tt.value, got, tt.want)
+			}
+		})
+	}
+}
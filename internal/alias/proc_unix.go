@@ -0,0 +1,40 @@
+//go:build !windows
+
+package alias
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup makes the command the leader of a new process group,
+// so it and every process it spawns can be signalled together.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// signalProcessGroup sends sig to the command's whole process group.
+func signalProcessGroup(cmd *exec.Cmd, sig os.Signal) error {
+	if cmd.Process == nil {
+		return nil
+	}
+
+	s, ok := sig.(syscall.Signal)
+	if !ok {
+		return cmd.Process.Signal(sig)
+	}
+
+	// A negative PID signals every process in the group
+	return syscall.Kill(-cmd.Process.Pid, s)
+}
+
+// killProcessGroup kills the command and everything it spawned.
+func killProcessGroup(cmd *exec.Cmd) error {
+	return signalProcessGroup(cmd, syscall.SIGKILL)
+}
+
+// isElevated reports whether aliasly is running as root.
+func isElevated() bool {
+	return os.Geteuid() == 0
+}
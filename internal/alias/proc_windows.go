@@ -0,0 +1,35 @@
+//go:build windows
+
+package alias
+
+import (
+	"os"
+	"os/exec"
+
+	"golang.org/x/sys/windows"
+)
+
+// setProcessGroup is a no-op on Windows, where process groups
+// work differently.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// signalProcessGroup sends sig to the command's process.
+// Windows only supports killing, so other signals kill too.
+func signalProcessGroup(cmd *exec.Cmd, sig os.Signal) error {
+	return killProcessGroup(cmd)
+}
+
+// killProcessGroup kills the command's process.
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return cmd.Process.Kill()
+}
+
+// isElevated reports whether aliasly is running with administrator
+// rights. This is a best-effort check of the process token; if it
+// can't be determined, it assumes not elevated.
+func isElevated() bool {
+	return windows.GetCurrentProcessToken().IsElevated()
+}
@@ -0,0 +1,76 @@
+package alias
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxSuggestDistance is the largest edit distance at which a name is
+// still considered a likely typo of another.
+const maxSuggestDistance = 2
+
+// SuggestNames returns the names in candidates that are close to name,
+// best match first. A candidate is close if it starts with name, or if
+// it is at most a couple of edits (insertions, deletions, substitutions)
+// away from it. Comparison ignores case.
+func SuggestNames(name string, candidates []string) []string {
+	type suggestion struct {
+		name     string
+		distance int
+	}
+
+	lower := strings.ToLower(name)
+	suggestions := make([]suggestion, 0)
+	for _, candidate := range candidates {
+		c := strings.ToLower(candidate)
+		distance := levenshtein(lower, c)
+
+		// Prefix matches rank just after exact-ish typos
+		if distance > maxSuggestDistance && lower != "" && strings.HasPrefix(c, lower) {
+			distance = maxSuggestDistance + 1
+		}
+		if distance > maxSuggestDistance+1 {
+			continue
+		}
+		suggestions = append(suggestions, suggestion{name: candidate, distance: distance})
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].distance != suggestions[j].distance {
+			return suggestions[i].distance < suggestions[j].distance
+		}
+		return suggestions[i].name < suggestions[j].name
+	})
+
+	names := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		names[i] = s.name
+	}
+	return names
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// prev and curr hold two rows of the distance matrix
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
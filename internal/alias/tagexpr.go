@@ -0,0 +1,184 @@
+package alias
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// TagExpr is a compiled boolean expression over alias tags, such as
+// "git and not deprecated" or "docker or k8s".
+//
+// The grammar supports the keywords and, or, not (case-insensitive)
+// and parentheses for grouping. "not" binds tightest, then "and", then
+// "or". A single tag name on its own is the simplest expression.
+type TagExpr struct {
+	root tagNode
+}
+
+// tagNode is a node in the parsed expression tree.
+type tagNode interface {
+	match(tags map[string]bool) bool
+}
+
+type tagLeaf string
+
+type tagNot struct{ operand tagNode }
+
+type tagAnd struct{ left, right tagNode }
+
+type tagOr struct{ left, right tagNode }
+
+func (n tagLeaf) match(tags map[string]bool) bool { return tags[string(n)] }
+func (n tagNot) match(tags map[string]bool) bool  { return !n.operand.match(tags) }
+func (n tagAnd) match(tags map[string]bool) bool  { return n.left.match(tags) && n.right.match(tags) }
+func (n tagOr) match(tags map[string]bool) bool   { return n.left.match(tags) || n.right.match(tags) }
+
+// ParseTagExpr compiles a tag expression.
+// Returns an error describing the problem if the expression is invalid.
+func ParseTagExpr(expr string) (*TagExpr, error) {
+	p := &tagParser{tokens: tokenizeTagExpr(expr)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty tag expression")
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid tag expression %q: %w", expr, err)
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("invalid tag expression %q: unexpected %q", expr, p.tokens[p.pos])
+	}
+
+	return &TagExpr{root: root}, nil
+}
+
+// Match reports whether the given tags satisfy the expression.
+// Tags are compared case-insensitively.
+func (e *TagExpr) Match(tags []string) bool {
+	set := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		set[strings.ToLower(t)] = true
+	}
+	return e.root.match(set)
+}
+
+// tokenizeTagExpr splits an expression into words and parentheses.
+func tokenizeTagExpr(expr string) []string {
+	var tokens []string
+	var current strings.Builder
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range expr {
+		switch {
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case unicode.IsSpace(r):
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// tagParser is a small recursive descent parser for tag expressions.
+type tagParser struct {
+	tokens []string
+	pos    int
+}
+
+// peek returns the next token in lowercase, or "" at the end.
+func (p *tagParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return strings.ToLower(p.tokens[p.pos])
+}
+
+// parseOr parses: and ("or" and)*
+func (p *tagParser) parseOr() (tagNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == "or" {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = tagOr{left, right}
+	}
+
+	return left, nil
+}
+
+// parseAnd parses: not ("and" not)*
+func (p *tagParser) parseAnd() (tagNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == "and" {
+		p.pos++
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = tagAnd{left, right}
+	}
+
+	return left, nil
+}
+
+// parseNot parses: "not" not | primary
+func (p *tagParser) parseNot() (tagNode, error) {
+	if p.peek() == "not" {
+		p.pos++
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return tagNot{operand}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+// parsePrimary parses: "(" or ")" | tag
+func (p *tagParser) parsePrimary() (tagNode, error) {
+	token := p.peek()
+
+	switch token {
+	case "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case "(":
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return inner, nil
+	case ")", "and", "or":
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+
+	p.pos++
+	return tagLeaf(token), nil
+}
@@ -0,0 +1,109 @@
+package alias
+
+import (
+	"fmt"
+	"strings"
+
+	"aliasly/internal/config"
+)
+
+// Severities of validation issues.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Issue is a single problem found in an alias by ValidateConfig.
+type Issue struct {
+	// Alias is the name of the alias the issue was found in
+	Alias string `json:"alias"`
+
+	// Index is the position of the alias in the config (0-based),
+	// which identifies it even when the name is empty or duplicated
+	Index int `json:"index"`
+
+	// Severity is SeverityError or SeverityWarning
+	Severity string `json:"severity"`
+
+	// Message describes the problem
+	Message string `json:"message"`
+}
+
+// IsError reports whether the issue is an error rather than a warning.
+func (i Issue) IsError() bool {
+	return i.Severity == SeverityError
+}
+
+// ValidateConfig checks every alias in cfg and returns all problems found,
+// in the order of the aliases.
+//
+// Errors are empty commands, duplicate or invalid names, undefined
+// placeholders, required parameters with defaults, and invalid variadic
+// parameters, types or constraints. Parameters that are never used are warnings.
+func ValidateConfig(cfg *config.Config) []Issue {
+	var issues []Issue
+	seen := make(map[string]bool)
+
+	for i, a := range cfg.Aliases {
+		add := func(severity, message string) {
+			issues = append(issues, Issue{Alias: a.Name, Index: i, Severity: severity, Message: message})
+		}
+
+		if a.Name == "" {
+			add(SeverityError, "name is empty")
+		} else if err := ValidateNameWithPolicy(a.Name, cfg.Settings.NamePolicy); err != nil {
+			add(SeverityError, err.Error())
+		}
+
+		if seen[a.Name] {
+			add(SeverityError, fmt.Sprintf("duplicate alias name '%s'", a.Name))
+		}
+		seen[a.Name] = true
+
+		if strings.TrimSpace(a.Command) == "" {
+			add(SeverityError, "command is empty")
+		}
+
+		if err := CheckPlaceholders(a); err != nil {
+			add(SeverityError, err.Error())
+		}
+
+		for _, p := range a.Params {
+			if p.Required && p.Default != "" {
+				add(SeverityError, fmt.Sprintf("parameter '%s' is required but has a default, which is never used", p.Name))
+			}
+		}
+
+		if err := ValidateVariadic(a); err != nil {
+			add(SeverityError, err.Error())
+		}
+
+		if err := ValidateConstraints(a); err != nil {
+			add(SeverityError, err.Error())
+		}
+
+		for _, name := range UnusedParams(a) {
+			add(SeverityWarning, fmt.Sprintf("parameter '%s' is not used in the command", name))
+		}
+	}
+
+	return issues
+}
+
+// ShadowIssues returns a warning for every alias in cfg whose name
+// matches a command in $PATH. It is kept out of ValidateConfig because
+// the result depends on the machine it runs on, not just the config.
+func ShadowIssues(cfg *config.Config) []Issue {
+	var issues []Issue
+	for i, a := range cfg.Aliases {
+		if path, ok := ShadowedCommand(a.Name); ok {
+			issues = append(issues, Issue{
+				Alias:    a.Name,
+				Index:    i,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("name shadows the command %s in $PATH", path),
+			})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultBackupCount is how many config backups are kept when
+// settings.backup_count isn't set.
+const DefaultBackupCount = 5
+
+// backupTimeFormat is used in backup file names. It sorts
+// chronologically and contains no characters Windows rejects.
+const backupTimeFormat = "20060102-150405.000"
+
+// BackupInfo describes a config backup in the backups directory.
+type BackupInfo struct {
+	// Path is the full path to the backup file
+	Path string
+
+	// Time is when the backup was taken
+	Time time.Time
+}
+
+// GetBackupsDir returns the directory holding rotated config backups,
+// inside the state directory.
+func GetBackupsDir() string {
+	return statePath("backups")
+}
+
+// rotateBackups copies the config file at configPath into the backups
+// directory before it is overwritten with data, then prunes old backups
+// so only the most recent keep remain.
+// Nothing is copied if there is no config file yet or its contents
+// wouldn't change.
+func rotateBackups(configPath string, data []byte, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+
+	current, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(current, data) {
+		return nil
+	}
+
+	backupsDir := GetBackupsDir()
+	if err := os.MkdirAll(backupsDir, 0700); err != nil {
+		return err
+	}
+
+	// Keep the extension so the format is known when restoring
+	name := "config-" + time.Now().Format(backupTimeFormat) + filepath.Ext(configPath)
+	if err := WriteFileAtomic(filepath.Join(backupsDir, name), current, 0600); err != nil {
+		return err
+	}
+
+	backups, err := ListBackups()
+	if err != nil {
+		return err
+	}
+	for _, b := range backups[min(keep, len(backups)):] {
+		os.Remove(b.Path)
+	}
+
+	return nil
+}
+
+// ListBackups returns the config backups, newest first.
+// It returns an empty list if there are no backups yet.
+func ListBackups() ([]BackupInfo, error) {
+	entries, err := os.ReadDir(GetBackupsDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backups directory: %w", err)
+	}
+
+	var backups []BackupInfo
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, "config-") {
+			continue
+		}
+
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, "config-"), filepath.Ext(name))
+		t, err := time.ParseInLocation(backupTimeFormat, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+
+		backups = append(backups, BackupInfo{
+			Path: filepath.Join(GetBackupsDir(), name),
+			Time: t,
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Time.After(backups[j].Time)
+	})
+
+	return backups, nil
+}
+
+// RestoreBackup replaces the current config with the backup at path.
+// The backup may be YAML or TOML; it is saved in the config file's format.
+// The config being replaced is itself backed up first, so a restore
+// can be undone.
+func RestoreBackup(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	cfg, err := ParseFormat(data, FormatFromPath(path))
+	if err != nil {
+		return fmt.Errorf("invalid backup %s: %w", filepath.Base(path), err)
+	}
+
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	globalConfig = cfg
+	loaded = true
+
+	return saveInternal()
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// AliasSummary is the name and description of an alias, which is all
+// shell completion needs.
+type AliasSummary struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// completionCache is the on-disk format of the completion cache.
+// It records which config file it was built from, and that file's
+// modification time, so it can tell when it's out of date.
+// It only holds the global aliases, along with whether project configs
+// are turned on, since the project config depends on the directory.
+type completionCache struct {
+	ConfigPath    string         `json:"config_path"`
+	ModTime       int64          `json:"mod_time"`
+	ProjectConfig bool           `json:"project_config"`
+	Aliases       []AliasSummary `json:"aliases"`
+}
+
+// getCompletionCachePath returns the path of the completion cache file.
+func getCompletionCachePath() string {
+	return filepath.Join(GetCacheDir(), "completion.json")
+}
+
+// GetAliasSummaries returns the name and description of every alias.
+//
+// It is used for shell completion, which runs on every Tab press, so
+// the result is cached. The cache is used as long as the config file
+// hasn't been modified since it was written; otherwise the config is
+// loaded and the cache rebuilt.
+func GetAliasSummaries() ([]AliasSummary, error) {
+	configPath := GetConfigFilePath()
+
+	var modTime int64
+	if info, err := os.Stat(configPath); err == nil {
+		modTime = info.ModTime().UnixNano()
+
+		if data, err := os.ReadFile(getCompletionCachePath()); err == nil {
+			var cache completionCache
+			if json.Unmarshal(data, &cache) == nil && cache.ConfigPath == configPath && cache.ModTime == modTime {
+				if !cache.ProjectConfig {
+					return cache.Aliases, nil
+				}
+				return mergeProjectSummaries(cache.Aliases), nil
+			}
+		}
+	}
+
+	cfg, err := Get()
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]AliasSummary, len(cfg.Aliases))
+	for i, a := range cfg.Aliases {
+		summaries[i] = AliasSummary{Name: a.Name, Description: a.Description}
+	}
+	projectConfig := cfg.Settings.ProjectConfig
+
+	// Loading may have created the config file, so check its time again.
+	// Failing to write the cache only makes the next completion slower.
+	if info, err := os.Stat(configPath); err == nil {
+		modTime = info.ModTime().UnixNano()
+		writeCompletionCache(completionCache{ConfigPath: configPath, ModTime: modTime, ProjectConfig: projectConfig, Aliases: summaries})
+	}
+
+	if !projectConfig {
+		return summaries, nil
+	}
+	return mergeProjectSummaries(summaries), nil
+}
+
+// mergeProjectSummaries adds the aliases of the closest project config
+// to the cached global summaries, replacing those with the same name.
+// A project config that can't be read is left out.
+func mergeProjectSummaries(summaries []AliasSummary) []AliasSummary {
+	_, aliases, err := readProjectConfig()
+	if err != nil || len(aliases) == 0 {
+		return summaries
+	}
+
+	merged := make([]AliasSummary, 0, len(summaries)+len(aliases))
+	project := make(map[string]bool, len(aliases))
+	for _, a := range aliases {
+		project[a.Name] = true
+	}
+	for _, s := range summaries {
+		if !project[s.Name] {
+			merged = append(merged, s)
+		}
+	}
+	for _, a := range aliases {
+		merged = append(merged, AliasSummary{Name: a.Name, Description: a.Description})
+	}
+
+	return merged
+}
+
+// writeCompletionCache writes the completion cache, ignoring errors.
+func writeCompletionCache(cache completionCache) {
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(GetCacheDir(), 0755); err != nil {
+		return
+	}
+
+	// Write to a temporary file and rename it into place,
+	// so a concurrent completion never reads a partial file
+	cachePath := getCompletionCachePath()
+	tmpPath := cachePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return
+	}
+	if err := os.Rename(tmpPath, cachePath); err != nil {
+		os.Remove(tmpPath)
+	}
+}
+
+// invalidateCompletionCache removes the completion cache so the next
+// completion rebuilds it. Called whenever the config is saved.
+func invalidateCompletionCache() {
+	os.Remove(getCompletionCachePath())
+}
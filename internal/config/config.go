@@ -1,25 +1,30 @@
 package config
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
 	"sync"
-
-	"github.com/spf13/viper"
-	"go.yaml.in/yaml/v3"
+	"syscall"
+	"unicode/utf8"
 )
 
 // Config represents the root configuration structure for aliasly.
 // It contains application settings and all defined aliases.
 type Config struct {
 	// Version is the config file format version (for future migrations)
-	Version int `mapstructure:"version" yaml:"version" json:"version"`
+	Version int `mapstructure:"version" yaml:"version" toml:"version" json:"version"`
 
 	// Settings contains global application settings
-	Settings Settings `mapstructure:"settings" yaml:"settings" json:"settings"`
+	Settings Settings `mapstructure:"settings" yaml:"settings" toml:"settings" json:"settings"`
 
 	// Aliases is the list of all defined command aliases
-	Aliases []Alias `mapstructure:"aliases" yaml:"aliases" json:"aliases"`
+	Aliases []Alias `mapstructure:"aliases" yaml:"aliases" toml:"aliases" json:"aliases"`
 }
 
 // Settings contains global configuration options that affect
@@ -27,43 +32,300 @@ type Config struct {
 type Settings struct {
 	// Shell is the shell to use for executing commands (e.g., "/bin/bash")
 	// If empty, the default shell will be detected automatically
-	Shell string `mapstructure:"shell" yaml:"shell" json:"shell"`
+	Shell string `mapstructure:"shell" yaml:"shell" toml:"shell" json:"shell"`
 
 	// Verbose, when true, prints the expanded command before running it
-	Verbose bool `mapstructure:"verbose" yaml:"verbose" json:"verbose"`
+	Verbose bool `mapstructure:"verbose" yaml:"verbose" toml:"verbose" json:"verbose"`
+
+	// UsePager, when true, pipes long output (like 'al list') through a pager
+	UsePager bool `mapstructure:"use_pager" yaml:"use_pager,omitempty" toml:"use_pager,omitempty" json:"use_pager"`
+
+	// Timeout is the default number of seconds a command may run
+	// before it is killed. 0 means no timeout.
+	Timeout int `mapstructure:"timeout" yaml:"timeout,omitempty" toml:"timeout,omitempty" json:"timeout"`
+
+	// QuoteParams, when true, shell-quotes every substituted parameter
+	// value so it's passed as a single literal argument
+	QuoteParams bool `mapstructure:"quote_params" yaml:"quote_params,omitempty" toml:"quote_params,omitempty" json:"quote_params"`
+
+	// NamePolicy controls which alias names are allowed
+	NamePolicy NamePolicy `mapstructure:"name_policy" yaml:"name_policy,omitempty" toml:"name_policy,omitempty" json:"name_policy"`
+
+	// WarnOnRoot, when true, refuses to run any alias as root
+	// (or as administrator on Windows) unless --yes is given
+	WarnOnRoot bool `mapstructure:"warn_on_root" yaml:"warn_on_root,omitempty" toml:"warn_on_root,omitempty" json:"warn_on_root"`
+
+	// TrackUsage controls whether alias runs are counted for 'al stats'.
+	// It is a pointer so that a missing setting means on.
+	// Use UsageTrackingEnabled to read it.
+	TrackUsage *bool `mapstructure:"track_usage" yaml:"track_usage,omitempty" toml:"track_usage,omitempty" json:"track_usage,omitempty"`
+
+	// BackupCount is how many previous versions of the config to keep
+	// in the backups directory (0 turns backups off).
+	// It is a pointer so that a missing setting means DefaultBackupCount.
+	// Use BackupLimit to read it.
+	BackupCount *int `mapstructure:"backup_count" yaml:"backup_count,omitempty" toml:"backup_count,omitempty" json:"backup_count,omitempty"`
+
+	// ConfirmPatterns lists text (e.g. "rm ", "kubectl delete") that, when
+	// found in an alias's expanded command, asks for confirmation before
+	// running it, as if the alias had confirm set. Matching ignores case.
+	ConfirmPatterns []string `mapstructure:"confirm_patterns" yaml:"confirm_patterns,omitempty" toml:"confirm_patterns,omitempty" json:"confirm_patterns,omitempty"`
+
+	// History, when true, records every command aliasly runs (with
+	// its time and exit code) for 'al history'. Off by default, since
+	// commands can contain private values.
+	History bool `mapstructure:"history" yaml:"history,omitempty" toml:"history,omitempty" json:"history"`
+
+	// HistorySize is how many history entries to keep
+	// (0 means DefaultHistorySize). Use HistoryLimit to read it.
+	HistorySize int `mapstructure:"history_size" yaml:"history_size,omitempty" toml:"history_size,omitempty" json:"history_size,omitempty"`
+
+	// ProjectConfig, when true, merges the aliases of the closest
+	// .aliasly.yaml in the current directory or its parents on top of
+	// the global ones. Off by default, so a cloned repository can't
+	// add aliases without the user opting in.
+	ProjectConfig bool `mapstructure:"project_config" yaml:"project_config,omitempty" toml:"project_config,omitempty" json:"project_config"`
+
+	// StrictArgs, when true, makes positional arguments that no
+	// parameter takes an error. Otherwise they're ignored with a warning.
+	StrictArgs bool `mapstructure:"strict_args" yaml:"strict_args,omitempty" toml:"strict_args,omitempty" json:"strict_args"`
+}
+
+// UsageTrackingEnabled reports whether alias runs should be counted.
+// Tracking is on unless track_usage is explicitly set to false.
+func (s Settings) UsageTrackingEnabled() bool {
+	return s.TrackUsage == nil || *s.TrackUsage
+}
+
+// BackupLimit returns how many config backups to keep.
+func (s Settings) BackupLimit() int {
+	if s.BackupCount == nil {
+		return DefaultBackupCount
+	}
+	return *s.BackupCount
+}
+
+// HistoryLimit returns how many history entries to keep.
+func (s Settings) HistoryLimit() int {
+	if s.HistorySize <= 0 {
+		return DefaultHistorySize
+	}
+	return s.HistorySize
+}
+
+// NamePolicy defines the rules alias names must follow.
+// The zero value matches the built-in rules: names start with a letter
+// and contain only letters, numbers, and hyphens, with no length limit.
+type NamePolicy struct {
+	// MaxLength is the maximum number of characters in a name (0 = no limit)
+	MaxLength int `mapstructure:"max_length" yaml:"max_length,omitempty" toml:"max_length,omitempty" json:"max_length"`
+
+	// AllowDot, when true, allows dots in names (e.g., "git.status")
+	AllowDot bool `mapstructure:"allow_dot" yaml:"allow_dot,omitempty" toml:"allow_dot,omitempty" json:"allow_dot"`
+
+	// AllowColon, when true, allows colons in names (e.g., "git:status")
+	AllowColon bool `mapstructure:"allow_colon" yaml:"allow_colon,omitempty" toml:"allow_colon,omitempty" json:"allow_colon"`
 }
 
 // Alias represents a single command alias.
 // An alias maps a short name to a longer command, optionally with parameters.
 type Alias struct {
 	// Name is the short name for the alias (e.g., "gs" for git status)
-	Name string `mapstructure:"name" yaml:"name" json:"name"`
+	Name string `mapstructure:"name" yaml:"name" toml:"name" json:"name"`
 
 	// Command is the actual command to run, may contain {{param}} placeholders
-	Command string `mapstructure:"command" yaml:"command" json:"command"`
+	Command string `mapstructure:"command" yaml:"command" toml:"command,multiline" json:"command"`
 
 	// Description is a human-readable explanation of what this alias does
-	Description string `mapstructure:"description" yaml:"description" json:"description"`
+	Description string `mapstructure:"description" yaml:"description" toml:"description" json:"description"`
+
+	// Notes is free-form text for longer details than fit in the
+	// one-line Description, e.g. "requires VPN" or a link to docs.
+	// It is only shown in detail views such as 'al show'.
+	Notes string `mapstructure:"notes" yaml:"notes,omitempty" toml:"notes,multiline,omitempty" json:"notes,omitempty"`
 
 	// Params defines the parameters that this alias accepts
-	Params []Param `mapstructure:"params" yaml:"params,omitempty" json:"params,omitempty"`
+	Params []Param `mapstructure:"params" yaml:"params,omitempty" toml:"params,omitempty" json:"params,omitempty"`
+
+	// WorkingDir is the directory to run the command in.
+	// Supports ~ and environment variables. If empty, the current directory is used.
+	WorkingDir string `mapstructure:"working_dir" yaml:"working_dir,omitempty" toml:"working_dir,omitempty" json:"working_dir,omitempty"`
+
+	// Timeout is the number of seconds the command may run before it is
+	// killed, overriding the global setting. 0 means use the global setting.
+	Timeout int `mapstructure:"timeout" yaml:"timeout,omitempty" toml:"timeout,omitempty" json:"timeout,omitempty"`
+
+	// TimeoutSignal is the signal sent when the timeout fires (e.g. "SIGTERM").
+	// If empty, the command is killed immediately.
+	TimeoutSignal string `mapstructure:"timeout_signal" yaml:"timeout_signal,omitempty" toml:"timeout_signal,omitempty" json:"timeout_signal,omitempty"`
+
+	// TimeoutGrace is the number of seconds to wait after TimeoutSignal
+	// before the command is killed if it is still running.
+	TimeoutGrace int `mapstructure:"timeout_grace" yaml:"timeout_grace,omitempty" toml:"timeout_grace,omitempty" json:"timeout_grace,omitempty"`
+
+	// Env holds environment variables to set when running the command.
+	// Values may contain {{param}} placeholders. They override any
+	// inherited variable with the same name.
+	Env map[string]string `mapstructure:"env" yaml:"env,omitempty" toml:"env,omitempty" json:"env,omitempty"`
+
+	// Tags are free-form labels used to group and filter aliases
+	Tags []string `mapstructure:"tags" yaml:"tags,omitempty" toml:"tags,omitempty" json:"tags,omitempty"`
+
+	// LoginShell, when true, runs the command in a login shell (-l -c)
+	// so functions and aliases defined in the user's profile are available
+	LoginShell bool `mapstructure:"login_shell" yaml:"login_shell,omitempty" toml:"login_shell,omitempty" json:"login_shell,omitempty"`
+
+	// Confirm, when true, asks for confirmation (showing the expanded
+	// command) before running. Useful for destructive commands.
+	Confirm bool `mapstructure:"confirm" yaml:"confirm,omitempty" toml:"confirm,omitempty" json:"confirm,omitempty"`
+
+	// RefuseRoot, when true, refuses to run the command as root
+	// (or as administrator on Windows) unless --yes is given
+	RefuseRoot bool `mapstructure:"refuse_root" yaml:"refuse_root,omitempty" toml:"refuse_root,omitempty" json:"refuse_root,omitempty"`
 }
 
+// Types a parameter's value can be declared as (see Param.Type).
+const (
+	ParamTypeString = "string"
+	ParamTypeInt    = "int"
+	ParamTypeBool   = "bool"
+	ParamTypePath   = "path"
+)
+
 // Param represents a parameter that can be passed to an alias.
 // Parameters are substituted into the command using {{paramName}} syntax.
 type Param struct {
 	// Name is the parameter name, used in {{name}} placeholders
-	Name string `mapstructure:"name" yaml:"name" json:"name"`
+	Name string `mapstructure:"name" yaml:"name" toml:"name" json:"name"`
 
 	// Description explains what this parameter is for
-	Description string `mapstructure:"description" yaml:"description" json:"description"`
+	Description string `mapstructure:"description" yaml:"description" toml:"description" json:"description"`
 
 	// Required, when true, means this parameter must be provided
-	Required bool `mapstructure:"required" yaml:"required" json:"required"`
+	Required bool `mapstructure:"required" yaml:"required" toml:"required" json:"required"`
 
 	// Default is the value to use if the parameter is not provided
 	// Only used when Required is false
-	Default string `mapstructure:"default" yaml:"default,omitempty" json:"default,omitempty"`
+	Default string `mapstructure:"default" yaml:"default,omitempty" toml:"default,omitempty" json:"default,omitempty"`
+
+	// Quote, when true, shell-quotes the value before substituting it,
+	// so spaces, quotes, and metacharacters like ; can't break the command
+	Quote bool `mapstructure:"quote" yaml:"quote,omitempty" toml:"quote,omitempty" json:"quote,omitempty"`
+
+	// Variadic, when true, makes this parameter absorb all remaining
+	// positional arguments, joined by spaces. Only the last parameter
+	// of an alias can be variadic.
+	Variadic bool `mapstructure:"variadic" yaml:"variadic,omitempty" toml:"variadic,omitempty" json:"variadic,omitempty"`
+
+	// EnvVar is an environment variable to read the value from when
+	// the parameter isn't given as an argument. It takes precedence
+	// over Default, so it suits secrets like tokens.
+	EnvVar string `mapstructure:"env_var" yaml:"env_var,omitempty" toml:"env_var,omitempty" json:"env_var,omitempty"`
+
+	// Validate is a regular expression the whole value must match
+	// (e.g. "[a-zA-Z0-9_-]+"). Empty means any value is allowed.
+	Validate string `mapstructure:"validate" yaml:"validate,omitempty" toml:"validate,omitempty" json:"validate,omitempty"`
+
+	// Type is the kind of value the parameter takes: string (the
+	// default when empty), int, bool (true or false) or path. Values
+	// of other types are checked before the command runs, and a
+	// leading ~ in a path is expanded to the home directory.
+	Type string `mapstructure:"type" yaml:"type,omitempty" toml:"type,omitempty" json:"type,omitempty"`
+
+	// MustExist, when true, requires the value of a path parameter to
+	// be an existing file or directory.
+	MustExist bool `mapstructure:"must_exist" yaml:"must_exist,omitempty" toml:"must_exist,omitempty" json:"must_exist,omitempty"`
+
+	// Choices lists the only values this parameter accepts
+	// (e.g. staging, production). Empty means any value is allowed.
+	Choices []string `mapstructure:"choices" yaml:"choices,omitempty" toml:"choices,omitempty" json:"choices,omitempty"`
+
+	// Secret, when true, masks the value wherever aliasly shows the
+	// command (verbose output, dry runs, confirmations, history) and
+	// hides it while it's typed at a prompt. The command itself still
+	// gets the real value.
+	Secret bool `mapstructure:"secret" yaml:"secret,omitempty" toml:"secret,omitempty" json:"secret,omitempty"`
+}
+
+// Clone returns a deep copy of the alias, so changing the copy's
+// params, choices, tags or env doesn't affect the original.
+func (a Alias) Clone() Alias {
+	c := a
+
+	if a.Params != nil {
+		c.Params = make([]Param, len(a.Params))
+		for i, p := range a.Params {
+			c.Params[i] = p
+			if p.Choices != nil {
+				c.Params[i].Choices = append([]string(nil), p.Choices...)
+			}
+		}
+	}
+
+	if a.Tags != nil {
+		c.Tags = append([]string(nil), a.Tags...)
+	}
+
+	if a.Env != nil {
+		c.Env = make(map[string]string, len(a.Env))
+		for key, value := range a.Env {
+			c.Env[key] = value
+		}
+	}
+
+	return c
+}
+
+// ErrNotAliaslyConfig is returned by Parse when the data doesn't look
+// like an aliasly config file.
+var ErrNotAliaslyConfig = errors.New("this doesn't look like an aliasly config")
+
+// ErrConfigReadOnly is returned when the config can't be written
+// because its directory is read-only (e.g. a read-only mount).
+var ErrConfigReadOnly = errors.New("config directory is read-only")
+
+// readOnlyError returns an ErrConfigReadOnly error for dir that tells
+// the user how to work around it.
+func readOnlyError(dir string) error {
+	return fmt.Errorf("%w: %s; set ALIASLY_CONFIG_DIR to a writable location", ErrConfigReadOnly, dir)
+}
+
+// isReadOnlyErr reports whether err means a path couldn't be written
+// because of a read-only file system or missing permissions.
+func isReadOnlyErr(err error) bool {
+	return errors.Is(err, syscall.EROFS) || errors.Is(err, fs.ErrPermission)
+}
+
+// Parse decodes YAML (or JSON) config data, such as an imported file.
+// It rejects binary data and documents without an "aliases" key, which
+// would otherwise decode into an empty config and silently do nothing.
+func Parse(data []byte) (*Config, error) {
+	return ParseFormat(data, FormatYAML)
+}
+
+// ParseFormat is like Parse, but decodes data in the given format
+// ("yaml" or "toml").
+func ParseFormat(data []byte, format string) (*Config, error) {
+	// Binary files usually contain NUL bytes or invalid UTF-8
+	if !utf8.Valid(data) || bytes.IndexByte(data, 0) != -1 {
+		return nil, fmt.Errorf("%w: file is not valid UTF-8 text", ErrNotAliaslyConfig)
+	}
+
+	// Decode into a generic map first to check the aliases key exists
+	var raw map[string]interface{}
+	if err := Unmarshal(data, &raw, format); err != nil {
+		return nil, fmt.Errorf("invalid %s format: %w", strings.ToUpper(format), err)
+	}
+	if _, ok := raw["aliases"]; !ok {
+		return nil, fmt.Errorf("%w: missing 'aliases' key", ErrNotAliaslyConfig)
+	}
+
+	var cfg Config
+	if err := Unmarshal(data, &cfg, format); err != nil {
+		return nil, fmt.Errorf("invalid %s format: %w", strings.ToUpper(format), err)
+	}
+
+	return &cfg, nil
 }
 
 // globalConfig holds the currently loaded configuration.
@@ -89,8 +351,9 @@ func Load() error {
 
 // loadInternal is the internal load function that assumes the lock is already held.
 func loadInternal() error {
-	// Ensure the config directory exists before trying to read/write
-	if err := EnsureConfigDir(); err != nil {
+	// Ensure the config directory exists before trying to read/write.
+	// A read-only location is fine as long as we only need to read.
+	if err := EnsureConfigDir(); err != nil && !errors.Is(err, ErrConfigReadOnly) {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
@@ -98,30 +361,41 @@ func loadInternal() error {
 
 	// Check if config file exists
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		// Config doesn't exist, create a default one
+		// Config doesn't exist, create a default one.
+		// Users who want a clean slate can skip the starter aliases
+		// by setting ALIASLY_NO_DEFAULTS.
 		globalConfig = createDefaultConfig()
+		if os.Getenv("ALIASLY_NO_DEFAULTS") != "" {
+			globalConfig.Aliases = []Alias{}
+		}
 		loaded = true
-		return saveInternal()
-	}
 
-	// Set up Viper to read our config file
-	// Viper is a popular Go library for configuration management
-	viper.SetConfigFile(configPath)
-	viper.SetConfigType("yaml")
+		// If the default can't be written, keep using it in memory so
+		// read-only commands (list, run, export) still work
+		if err := saveInternal(); err != nil && !errors.Is(err, ErrConfigReadOnly) {
+			return err
+		}
+		return loadProjectInternal()
+	}
 
 	// Read the config file
-	if err := viper.ReadInConfig(); err != nil {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
 		return fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	// Unmarshal (convert) the YAML into our Config struct
+	// Unmarshal (convert) the YAML or TOML into our Config struct.
+	// We decode with the YAML/TOML libraries directly rather than through
+	// Viper, because Viper lowercases all map keys, which would break
+	// case-sensitive keys like environment variable names.
 	globalConfig = &Config{}
-	if err := viper.Unmarshal(globalConfig); err != nil {
+	if err := Unmarshal(data, globalConfig, FormatFromPath(configPath)); err != nil {
 		return fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	lastHash = sha256.Sum256(data)
 	loaded = true
-	return nil
+	return loadProjectInternal()
 }
 
 // Save writes the current configuration to disk.
@@ -142,26 +416,142 @@ func saveInternal() error {
 
 	// Ensure config directory exists
 	if err := EnsureConfigDir(); err != nil {
+		if errors.Is(err, ErrConfigReadOnly) {
+			return err
+		}
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	// Marshal (convert) our Config struct to YAML format
-	// yaml.Marshal converts Go structs to YAML text
-	data, err := yaml.Marshal(globalConfig)
+	// Marshal (convert) our Config struct to the config file's format,
+	// YAML unless the user keeps a config.toml
+	configPath := GetConfigFilePath()
+	data, err := Marshal(globalConfig, FormatFromPath(configPath))
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	// Write the YAML to the config file
+	// Keep a copy of the previous version so a bad edit can be undone
+	if err := rotateBackups(configPath, data, globalConfig.Settings.BackupLimit()); err != nil {
+		if isReadOnlyErr(err) {
+			return readOnlyError(GetConfigDir())
+		}
+		return fmt.Errorf("failed to back up config: %w", err)
+	}
+
+	// Write the data to the config file atomically, so a crash or a
+	// full disk can't leave a truncated config behind
 	// 0644 = rw-r--r-- (owner can read/write, others can read)
-	configPath := GetConfigFilePath()
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
+	if err := WriteFileAtomic(configPath, data, 0644); err != nil {
+		if isReadOnlyErr(err) {
+			return readOnlyError(GetConfigDir())
+		}
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
+	lastHash = sha256.Sum256(data)
+
+	// The cached alias names are now out of date
+	invalidateCompletionCache()
+
+	return nil
+}
+
+// WriteFileAtomic writes data to a temporary file in the same directory
+// as path and renames it over path, so readers see either the old or the
+// new contents, never a partial write.
+// An existing file keeps its permissions; a new one is created with perm.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	// The temp file must be in the same directory for the rename to be atomic
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	// Clean up the temp file if anything below fails
+	success := false
+	defer func() {
+		if !success {
+			tmp.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		return err
+	}
+	// Flush to disk before the rename, or a crash could still leave
+	// an empty file in place of the config
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
 
+	success = true
 	return nil
 }
 
+// GetBackupFilePath returns the path used for config backups.
+func GetBackupFilePath() string {
+	return GetConfigFilePath() + ".backup"
+}
+
+// Backup copies the current config file to the backup location.
+// Returns the backup path, or an empty string if there was no
+// config file to back up.
+func Backup() (string, error) {
+	data, err := os.ReadFile(GetConfigFilePath())
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	backupPath := GetBackupFilePath()
+	if err := os.WriteFile(backupPath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to create backup: %w", err)
+	}
+
+	return backupPath, nil
+}
+
+// Reset replaces the current configuration with the default one,
+// including the starter aliases, and saves it.
+func Reset() error {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	globalConfig = createDefaultConfig()
+	loaded = true
+
+	return saveInternal()
+}
+
+// Replace replaces the current configuration with cfg and saves it,
+// e.g. when importing a whole config. Like any save, the previous
+// version is kept as a backup and the file is written atomically.
+func Replace(cfg *Config) error {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	globalConfig = cfg
+	loaded = true
+
+	return saveInternal()
+}
+
 // ensureLoaded makes sure the config is loaded before proceeding.
 // Must be called while holding the write lock.
 func ensureLoaded() error {
@@ -195,6 +585,13 @@ func FindAlias(name string) (Alias, bool) {
 		return Alias{}, false
 	}
 
+	// Project aliases take precedence over global ones
+	for _, alias := range projectAliases {
+		if alias.Name == name {
+			return alias, true
+		}
+	}
+
 	// Linear search through aliases
 	// For a typical number of aliases (< 100), this is fast enough
 	for _, alias := range globalConfig.Aliases {
@@ -222,6 +619,9 @@ func AddAlias(alias Alias) error {
 			return fmt.Errorf("alias '%s' already exists", alias.Name)
 		}
 	}
+	if err := projectNameError(alias.Name); err != nil {
+		return err
+	}
 
 	globalConfig.Aliases = append(globalConfig.Aliases, alias)
 
@@ -229,7 +629,8 @@ func AddAlias(alias Alias) error {
 }
 
 // RemoveAlias removes an alias from the configuration by name.
-// Returns an error if the alias doesn't exist.
+// Returns an error if the alias doesn't exist or comes from the
+// project config.
 func RemoveAlias(name string) error {
 	configMutex.Lock()
 	defer configMutex.Unlock()
@@ -237,6 +638,9 @@ func RemoveAlias(name string) error {
 	if err := ensureLoaded(); err != nil {
 		return err
 	}
+	if err := projectAliasError(name); err != nil {
+		return err
+	}
 
 	// Find and remove the alias
 	found := false
@@ -258,8 +662,149 @@ func RemoveAlias(name string) error {
 	return saveInternal()
 }
 
+// RemoveAliases removes the named aliases and saves the config once.
+// Names that don't exist are skipped rather than treated as errors.
+// It returns the names that were removed and those that weren't found,
+// each in the order given. Nothing is removed if any name is a project
+// alias.
+func RemoveAliases(names []string) (removed, notFound []string, err error) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	if err := ensureLoaded(); err != nil {
+		return nil, nil, err
+	}
+	for _, name := range names {
+		if err := projectAliasError(name); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	toRemove := make(map[string]bool, len(names))
+	for _, name := range names {
+		toRemove[name] = true
+	}
+
+	// Build new slice without the removed aliases
+	var newAliases []Alias
+	exists := make(map[string]bool)
+	for _, alias := range globalConfig.Aliases {
+		if toRemove[alias.Name] {
+			exists[alias.Name] = true
+			continue
+		}
+		newAliases = append(newAliases, alias)
+	}
+
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if exists[name] {
+			removed = append(removed, name)
+		} else {
+			notFound = append(notFound, name)
+		}
+	}
+
+	if len(removed) == 0 {
+		return removed, notFound, nil
+	}
+
+	globalConfig.Aliases = newAliases
+	if err := saveInternal(); err != nil {
+		return nil, nil, err
+	}
+
+	return removed, notFound, nil
+}
+
+// ClearAliases removes every alias from the configuration.
+// If keepExamples is true, the default starter aliases are added back.
+func ClearAliases(keepExamples bool) error {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	if err := ensureLoaded(); err != nil {
+		return err
+	}
+
+	globalConfig.Aliases = []Alias{}
+	if keepExamples {
+		globalConfig.Aliases = createDefaultConfig().Aliases
+	}
+
+	return saveInternal()
+}
+
+// RenameAlias changes the name of an alias in place, so it keeps its
+// position in the list, and moves its usage stats to the new name.
+// {{alias:oldName}} references in other aliases' commands are rewritten
+// to the new name in the same save. Aliases from the project config
+// can't be changed, so their references are left as they are.
+// Returns an error if oldName doesn't exist or comes from the project
+// config, or newName is already taken.
+func RenameAlias(oldName, newName string) error {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	if err := ensureLoaded(); err != nil {
+		return err
+	}
+	if err := projectAliasError(oldName); err != nil {
+		return err
+	}
+	if err := projectNameError(newName); err != nil {
+		return err
+	}
+
+	index := -1
+	for i, a := range globalConfig.Aliases {
+		if a.Name == newName {
+			return fmt.Errorf("alias '%s' already exists", newName)
+		}
+		if a.Name == oldName {
+			index = i
+		}
+	}
+
+	if index == -1 {
+		return fmt.Errorf("alias '%s' not found", oldName)
+	}
+
+	// Keep the old commands to put back if saving fails
+	oldRef, newRef := "{{alias:"+oldName+"}}", "{{alias:"+newName+"}}"
+	oldCommands := make(map[int]string)
+	for i, a := range globalConfig.Aliases {
+		if strings.Contains(a.Command, oldRef) {
+			oldCommands[i] = a.Command
+			globalConfig.Aliases[i].Command = strings.ReplaceAll(a.Command, oldRef, newRef)
+		}
+	}
+
+	globalConfig.Aliases[index].Name = newName
+	if err := saveInternal(); err != nil {
+		globalConfig.Aliases[index].Name = oldName
+		for i, command := range oldCommands {
+			globalConfig.Aliases[i].Command = command
+		}
+		return err
+	}
+
+	// The rename itself succeeded, so failing to move the stats only
+	// loses the run history
+	if err := renameStats(oldName, newName); err != nil {
+		return fmt.Errorf("alias renamed, but failed to move its usage stats: %w", err)
+	}
+
+	return nil
+}
+
 // UpdateAlias updates an existing alias in the configuration.
-// Returns an error if the alias doesn't exist.
+// Returns an error if the alias doesn't exist or comes from the
+// project config.
 func UpdateAlias(alias Alias) error {
 	configMutex.Lock()
 	defer configMutex.Unlock()
@@ -267,6 +812,9 @@ func UpdateAlias(alias Alias) error {
 	if err := ensureLoaded(); err != nil {
 		return err
 	}
+	if err := projectAliasError(alias.Name); err != nil {
+		return err
+	}
 
 	// Find and update the alias
 	found := false
@@ -285,7 +833,59 @@ func UpdateAlias(alias Alias) error {
 	return saveInternal()
 }
 
-// GetAllAliases returns a copy of all aliases.
+// GetSettings returns a copy of the global settings.
+// Changing the copy, including its pointer fields and confirm patterns,
+// doesn't affect the config until it is passed to UpdateSettings.
+func GetSettings() (Settings, error) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	if err := ensureLoaded(); err != nil {
+		return Settings{}, err
+	}
+
+	settings := globalConfig.Settings
+	if settings.TrackUsage != nil {
+		trackUsage := *settings.TrackUsage
+		settings.TrackUsage = &trackUsage
+	}
+	if settings.BackupCount != nil {
+		backupCount := *settings.BackupCount
+		settings.BackupCount = &backupCount
+	}
+	if settings.ConfirmPatterns != nil {
+		settings.ConfirmPatterns = append([]string(nil), settings.ConfirmPatterns...)
+	}
+	return settings, nil
+}
+
+// UpdateSettings replaces the global settings and saves the config.
+func UpdateSettings(settings Settings) error {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	if err := ensureLoaded(); err != nil {
+		return err
+	}
+
+	globalConfig.Settings = settings
+	return saveInternal()
+}
+
+// IncrementUsage increments the usage counter of an alias, as shown by
+// 'al stats'. The count is kept in the stats file rather than the config,
+// and RecordUsage updates it under a lock after re-reading it from disk,
+// so concurrent runs (e.g. the web UI and a CLI run) don't lose increments.
+// Returns an error if the alias doesn't exist.
+func IncrementUsage(name string) error {
+	if _, ok := FindAlias(name); !ok {
+		return fmt.Errorf("alias '%s' not found", name)
+	}
+	return RecordUsage(name)
+}
+
+// GetAllAliases returns a copy of all aliases, with the project
+// config's aliases merged on top if there is one.
 func GetAllAliases() ([]Alias, error) {
 	configMutex.Lock()
 	defer configMutex.Unlock()
@@ -295,10 +895,7 @@ func GetAllAliases() ([]Alias, error) {
 	}
 
 	// Return a copy to prevent external modification
-	aliases := make([]Alias, len(globalConfig.Aliases))
-	copy(aliases, globalConfig.Aliases)
-
-	return aliases, nil
+	return mergeProjectAliases(globalConfig.Aliases), nil
 }
 
 // createDefaultConfig creates a new Config with sensible defaults
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"go.yaml.in/yaml/v3"
+)
+
+// Supported config file formats.
+const (
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+// FormatFromPath infers the config format from a file's extension.
+// Files ending in .toml are TOML; anything else is treated as YAML,
+// which also covers JSON since YAML is a superset of it.
+func FormatFromPath(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		return FormatTOML
+	}
+	return FormatYAML
+}
+
+// FileFormat returns the format of the active config file.
+func FileFormat() string {
+	return FormatFromPath(GetConfigFilePath())
+}
+
+// Marshal encodes v in the given format ("yaml" or "toml").
+func Marshal(v interface{}, format string) ([]byte, error) {
+	switch format {
+	case FormatYAML:
+		return yaml.Marshal(v)
+	case FormatTOML:
+		return toml.Marshal(v)
+	default:
+		return nil, fmt.Errorf("unknown config format '%s'", format)
+	}
+}
+
+// Unmarshal decodes data in the given format ("yaml" or "toml") into v.
+func Unmarshal(data []byte, v interface{}, format string) error {
+	switch format {
+	case FormatYAML:
+		return yaml.Unmarshal(data, v)
+	case FormatTOML:
+		return toml.Unmarshal(data, v)
+	default:
+		return fmt.Errorf("unknown config format '%s'", format)
+	}
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultHistorySize is how many history entries are kept when
+// settings.history_size isn't set.
+const DefaultHistorySize = 1000
+
+// HistoryEntry records one run of an alias.
+type HistoryEntry struct {
+	// Time is when the command was started
+	Time time.Time `json:"time"`
+
+	// Alias is the name of the alias that was run
+	Alias string `json:"alias"`
+
+	// Command is the expanded command that was executed
+	Command string `json:"command"`
+
+	// ExitCode is the command's exit code (-1 if it couldn't be
+	// started or was killed by its timeout)
+	ExitCode int `json:"exit_code"`
+
+	// Host is the name of the machine the command ran on, so runs can
+	// be told apart when history is synced between machines
+	Host string `json:"host,omitempty"`
+}
+
+// historyMutex serializes history updates within this process.
+var historyMutex sync.Mutex
+
+// GetHistoryFilePath returns the path of the command history file,
+// inside the state directory. It holds one JSON entry per line, so
+// runs can be appended cheaply.
+func GetHistoryFilePath() string {
+	return statePath("history.jsonl")
+}
+
+// AppendHistory adds an entry to the history file, then drops the
+// oldest entries so at most keep remain.
+func AppendHistory(entry HistoryEntry, keep int) error {
+	historyMutex.Lock()
+	defer historyMutex.Unlock()
+
+	if err := EnsureStateDir(); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal history entry: %w", err)
+	}
+
+	// Appending a single line is atomic enough that concurrent runs
+	// don't interleave their entries. History may hold the commands'
+	// arguments, so only the user can read it.
+	f, err := os.OpenFile(GetHistoryFilePath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	_, err = f.Write(append(line, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+
+	return trimHistory(keep)
+}
+
+// trimHistory rewrites the history file with only its last keep lines,
+// if it has more than that.
+func trimHistory(keep int) error {
+	data, err := os.ReadFile(GetHistoryFilePath())
+	if err != nil {
+		return fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	if len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) <= keep {
+		return nil
+	}
+
+	trimmed := bytes.Join(lines[len(lines)-keep:], nil)
+	if err := WriteFileAtomic(GetHistoryFilePath(), trimmed, 0600); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	return nil
+}
+
+// LoadHistory returns the recorded history entries, oldest first.
+// A missing history file means nothing has been recorded yet.
+// Lines that can't be parsed are skipped.
+func LoadHistory() ([]HistoryEntry, error) {
+	historyMutex.Lock()
+	defer historyMutex.Unlock()
+
+	f, err := os.Open(GetHistoryFilePath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+	defer f.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(f)
+	// Multi-line commands can make entries long
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	return entries, nil
+}
+
+// ClearHistory deletes the history file.
+func ClearHistory() error {
+	historyMutex.Lock()
+	defer historyMutex.Unlock()
+
+	if err := os.Remove(GetHistoryFilePath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete history file: %w", err)
+	}
+	return nil
+}
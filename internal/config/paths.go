@@ -4,6 +4,7 @@ package config
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 )
@@ -44,21 +45,119 @@ func GetConfigDir() string {
 }
 
 // GetConfigFilePath returns the full path to the config file.
-// The config file is always named "config.yaml" inside the config directory.
+// The config file is "config.yaml" inside the config directory, or
+// "config.toml" if only a TOML config exists there.
+// A new config is always created as YAML.
 func GetConfigFilePath() string {
-	return filepath.Join(GetConfigDir(), "config.yaml")
+	configDir := GetConfigDir()
+	yamlPath := filepath.Join(configDir, "config.yaml")
+
+	// Prefer YAML when both exist so behaviour doesn't change for
+	// existing users who happen to have a stray config.toml
+	if _, err := os.Stat(yamlPath); os.IsNotExist(err) {
+		tomlPath := filepath.Join(configDir, "config.toml")
+		if _, err := os.Stat(tomlPath); err == nil {
+			return tomlPath
+		}
+	}
+	return yamlPath
+}
+
+// GetCacheDir returns the directory for cached data that can be
+// regenerated at any time, like the completion cache.
+// It is $XDG_CACHE_HOME/aliasly (or the OS equivalent), falling back
+// to the config directory if the cache location can't be determined.
+func GetCacheDir() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return GetConfigDir()
+	}
+	return filepath.Join(cacheDir, "aliasly")
+}
+
+// GetStateDir returns the directory for data aliasly records as it runs,
+// like usage stats, command history and config backups, which is kept
+// out of the config directory so that stays small and easy to sync:
+//
+//  1. If ALIASLY_STATE_DIR environment variable is set, use that
+//  2. If XDG_STATE_HOME is set, use $XDG_STATE_HOME/aliasly
+//  3. Otherwise, use $HOME/.local/state/aliasly
+//
+// On Windows, where XDG_STATE_HOME is rarely set, the default is
+// %LocalAppData%\aliasly\state.
+func GetStateDir() string {
+	if envDir := os.Getenv("ALIASLY_STATE_DIR"); envDir != "" {
+		return envDir
+	}
+
+	if xdgState := os.Getenv("XDG_STATE_HOME"); xdgState != "" {
+		return filepath.Join(xdgState, "aliasly")
+	}
+
+	if runtime.GOOS == "windows" {
+		if localAppData, err := os.UserCacheDir(); err == nil {
+			return filepath.Join(localAppData, "aliasly", "state")
+		}
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		// Same fallback as the config directory
+		return GetConfigDir()
+	}
+
+	return filepath.Join(homeDir, ".local", "state", "aliasly")
+}
+
+// EnsureStateDir creates the state directory if it doesn't exist.
+// Only the owner can access it, since history may hold private values.
+func EnsureStateDir() error {
+	return os.MkdirAll(GetStateDir(), 0700)
+}
+
+// statePath returns the path of the named file or directory in the
+// state directory. Older versions kept state in the config directory,
+// so if it's still there (and not yet in the state directory), it is
+// moved over the first time it's used.
+func statePath(name string) string {
+	newPath := filepath.Join(GetStateDir(), name)
+
+	oldPath := filepath.Join(GetConfigDir(), name)
+	if oldPath == newPath {
+		return newPath
+	}
+	if _, err := os.Stat(oldPath); err != nil {
+		return newPath
+	}
+	if _, err := os.Stat(newPath); !os.IsNotExist(err) {
+		return newPath
+	}
+
+	// If the move fails (e.g. across file systems), the old state is
+	// left where it is and the new location starts empty
+	if EnsureStateDir() == nil {
+		os.Rename(oldPath, newPath)
+	}
+	return newPath
 }
 
 // EnsureConfigDir creates the config directory if it doesn't exist.
 // It uses 0755 permissions (owner can read/write/execute, others can read/execute).
-// Returns an error if the directory cannot be created.
+// Returns an error if the directory cannot be created, wrapping
+// ErrConfigReadOnly if that's because the location is read-only.
 func EnsureConfigDir() error {
 	configDir := GetConfigDir()
 
 	// os.MkdirAll creates the directory and any necessary parents
 	// It's safe to call even if the directory already exists
 	// 0755 = rwxr-xr-x (owner full access, others read+execute)
-	return os.MkdirAll(configDir, 0755)
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		if isReadOnlyErr(err) {
+			return readOnlyError(configDir)
+		}
+		return err
+	}
+	return nil
 }
 
 // GetDefaultShell returns the default shell for the current operating system.
@@ -72,7 +171,11 @@ func GetDefaultShell() string {
 	// Fall back to OS-specific defaults
 	switch runtime.GOOS {
 	case "windows":
-		// On Windows, use cmd.exe as the default
+		// On Windows, prefer PowerShell 7+ if it's installed,
+		// otherwise use cmd.exe
+		if _, err := exec.LookPath("pwsh"); err == nil {
+			return "pwsh"
+		}
 		return "cmd"
 	default:
 		// On Unix-like systems (macOS, Linux), use /bin/sh
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ProjectConfigFileName is the name of the project-local config file
+// aliasly looks for in the current directory and its parents.
+const ProjectConfigFileName = ".aliasly.yaml"
+
+// ErrProjectAlias is returned when changing an alias that comes from a
+// project config. aliasly only writes the global config, so project
+// aliases have to be edited in their file.
+var ErrProjectAlias = errors.New("alias is defined in a project config")
+
+// projectForced is set by EnableProjectConfig (the --local flag) to use
+// the project config even when settings.project_config is off.
+var projectForced bool
+
+// projectPath is the path of the project config in use, or "" if none.
+var projectPath string
+
+// projectAliases holds the aliases read from the project config.
+var projectAliases []Alias
+
+// FindProjectConfig looks for ProjectConfigFileName in dir and each of
+// its parents, like git does for .git, and returns the path of the
+// closest one.
+func FindProjectConfig(dir string) (string, bool) {
+	for {
+		path := filepath.Join(dir, ProjectConfigFileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// EnableProjectConfig turns on the project config for this process,
+// regardless of settings.project_config. If the config is already
+// loaded, the project config is read right away.
+func EnableProjectConfig() error {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	projectForced = true
+	if !loaded {
+		return nil
+	}
+	return loadProjectInternal()
+}
+
+// ProjectConfigPath returns the path of the project config whose
+// aliases are merged into the global ones, or "" if there is none.
+func ProjectConfigPath() string {
+	configMutex.RLock()
+	defer configMutex.RUnlock()
+
+	return projectPath
+}
+
+// IsProjectAlias reports whether the alias with the given name comes
+// from the project config.
+func IsProjectAlias(name string) bool {
+	configMutex.RLock()
+	defer configMutex.RUnlock()
+
+	return isProjectAliasInternal(name)
+}
+
+// loadProjectInternal reads the closest project config, if project
+// configs are enabled. Must be called while holding the write lock,
+// after globalConfig is loaded.
+func loadProjectInternal() error {
+	projectPath, projectAliases = "", nil
+
+	if !projectForced && !globalConfig.Settings.ProjectConfig {
+		return nil
+	}
+
+	path, aliases, err := readProjectConfig()
+	if err != nil {
+		return err
+	}
+
+	projectPath, projectAliases = path, aliases
+	return nil
+}
+
+// readProjectConfig finds the project config closest to the current
+// directory and returns its path and aliases, or "" if there is none.
+func readProjectConfig() (string, []Alias, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", nil, nil
+	}
+	path, found := FindProjectConfig(wd)
+	if !found {
+		return "", nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read project config %s: %w", path, err)
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse project config %s: %w", path, err)
+	}
+
+	return path, cfg.Aliases, nil
+}
+
+// isProjectAliasInternal is IsProjectAlias for callers already holding the lock.
+func isProjectAliasInternal(name string) bool {
+	for _, a := range projectAliases {
+		if a.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// projectAliasError returns an error wrapping ErrProjectAlias if name
+// is a project alias, so it isn't changed in the global config instead.
+// Must be called while holding the lock.
+func projectAliasError(name string) error {
+	if !isProjectAliasInternal(name) {
+		return nil
+	}
+	return fmt.Errorf("%w: '%s' comes from %s; edit that file instead", ErrProjectAlias, name, projectPath)
+}
+
+// projectNameError returns an error wrapping ErrProjectAlias if name is
+// taken by a project alias, so a new global alias with that name isn't
+// saved only to be hidden by it.
+// Must be called while holding the lock.
+func projectNameError(name string) error {
+	if !isProjectAliasInternal(name) {
+		return nil
+	}
+	return fmt.Errorf("%w: '%s' already exists in %s and would hide a global alias with that name", ErrProjectAlias, name, projectPath)
+}
+
+// mergeProjectAliases returns aliases with the project aliases layered
+// on top. A project alias replaces a global one with the same name in
+// its place; the others are added at the end.
+func mergeProjectAliases(aliases []Alias) []Alias {
+	merged := make([]Alias, len(aliases), len(aliases)+len(projectAliases))
+	copy(merged, aliases)
+
+	index := make(map[string]int, len(merged))
+	for i, a := range merged {
+		index[a.Name] = i
+	}
+	for _, a := range projectAliases {
+		if i, ok := index[a.Name]; ok {
+			merged[i] = a
+			continue
+		}
+		index[a.Name] = len(merged)
+		merged = append(merged, a)
+	}
+
+	return merged
+}
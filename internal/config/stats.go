@@ -0,0 +1,162 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// UsageStats records how often an alias has been run.
+type UsageStats struct {
+	// Count is the number of times the alias has been run
+	Count int `yaml:"count" json:"count"`
+
+	// LastUsed is when the alias was last run
+	LastUsed time.Time `yaml:"last_used" json:"last_used"`
+}
+
+// statsFile is the on-disk format of the stats file.
+type statsFile struct {
+	Aliases map[string]UsageStats `yaml:"aliases"`
+}
+
+// statsMutex serializes stats updates within this process.
+// Updates from other processes are serialized with a lock file.
+var statsMutex sync.Mutex
+
+// statsLockTimeout is how long to wait for another process's lock
+// before giving up, and how old a lock file must be to count as stale.
+const statsLockTimeout = 2 * time.Second
+
+// GetStatsFilePath returns the path of the usage stats file, inside
+// the state directory. Stats are kept out of config.yaml so running an
+// alias doesn't rewrite the config file.
+func GetStatsFilePath() string {
+	return statePath("stats.yaml")
+}
+
+// LoadStats reads the usage stats of all aliases, keyed by alias name.
+// A missing stats file means nothing has been recorded yet.
+func LoadStats() (map[string]UsageStats, error) {
+	statsMutex.Lock()
+	defer statsMutex.Unlock()
+
+	return loadStatsInternal()
+}
+
+// loadStatsInternal reads the stats file, assuming the lock is held.
+func loadStatsInternal() (map[string]UsageStats, error) {
+	data, err := os.ReadFile(GetStatsFilePath())
+	if os.IsNotExist(err) {
+		return map[string]UsageStats{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stats file: %w", err)
+	}
+
+	var file statsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse stats file: %w", err)
+	}
+
+	if file.Aliases == nil {
+		file.Aliases = map[string]UsageStats{}
+	}
+	return file.Aliases, nil
+}
+
+// RecordUsage increments the run count of an alias and sets its
+// last used time to now. It is safe to call from concurrent processes.
+func RecordUsage(name string) error {
+	return updateStats(func(stats map[string]UsageStats) {
+		s := stats[name]
+		s.Count++
+		s.LastUsed = time.Now()
+		stats[name] = s
+	})
+}
+
+// renameStats moves the usage stats of an alias to its new name.
+func renameStats(oldName, newName string) error {
+	// Nothing to move if nothing has been recorded yet
+	if _, err := os.Stat(GetStatsFilePath()); os.IsNotExist(err) {
+		return nil
+	}
+
+	return updateStats(func(stats map[string]UsageStats) {
+		if s, ok := stats[oldName]; ok {
+			stats[newName] = s
+			delete(stats, oldName)
+		}
+	})
+}
+
+// updateStats applies update to the stats and writes them back, while
+// holding the stats lock. It is safe to call from concurrent processes.
+func updateStats(update func(stats map[string]UsageStats)) error {
+	statsMutex.Lock()
+	defer statsMutex.Unlock()
+
+	if err := EnsureStateDir(); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	unlock, err := lockStatsFile()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Read the latest stats while holding the lock,
+	// so concurrent runs don't overwrite each other's counts
+	stats, err := loadStatsInternal()
+	if err != nil {
+		return err
+	}
+
+	update(stats)
+
+	data, err := yaml.Marshal(statsFile{Aliases: stats})
+	if err != nil {
+		return fmt.Errorf("failed to marshal stats: %w", err)
+	}
+
+	// Write atomically so readers never see a partially written file
+	if err := WriteFileAtomic(GetStatsFilePath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write stats file: %w", err)
+	}
+
+	return nil
+}
+
+// lockStatsFile takes an exclusive lock on the stats file by creating
+// a lock file next to it. It returns a function that releases the lock.
+// A lock left behind by a crashed process is removed once it is stale.
+func lockStatsFile() (func(), error) {
+	lockPath := GetStatsFilePath() + ".lock"
+	deadline := time.Now().Add(statsLockTimeout)
+
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock stats file: %w", err)
+		}
+
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > statsLockTimeout {
+			os.Remove(lockPath)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for stats lock %s", lockPath)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long the watcher waits after the last change to
+// the config file before reloading it. Editors often write a file in
+// several steps, and reloading once at the end avoids reading it
+// half-written.
+const watchDebounce = 100 * time.Millisecond
+
+// lastHash is the SHA-256 of the config file as aliasly last read or
+// wrote it. The watcher compares against it so aliasly's own saves
+// don't trigger a reload. Protected by configMutex.
+var lastHash [sha256.Size]byte
+
+// WatchConfig watches the config file and reloads it whenever it is
+// changed by something other than aliasly, such as a text editor.
+// onReload is called after each reload attempt, with the error if the
+// new file couldn't be read or parsed; the previous config stays in
+// effect in that case.
+//
+// The config directory is watched rather than the file itself, because
+// saves (aliasly's and many editors') replace the file with a new one.
+// Call the returned function to stop watching.
+func WatchConfig(onReload func(err error)) (stop func() error, err error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start config watcher: %w", err)
+	}
+	if err := watcher.Add(GetConfigDir()); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("failed to watch config directory: %w", err)
+	}
+
+	var timerMutex sync.Mutex
+	var timer *time.Timer
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Base(event.Name) != filepath.Base(GetConfigFilePath()) {
+					continue
+				}
+
+				timerMutex.Lock()
+				if timer != nil {
+					timer.Stop()
+				}
+				timer = time.AfterFunc(watchDebounce, func() {
+					if reloaded, err := reloadIfChanged(); reloaded || err != nil {
+						onReload(err)
+					}
+				})
+				timerMutex.Unlock()
+
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	return watcher.Close, nil
+}
+
+// reloadIfChanged reloads the config file if its content differs from
+// what aliasly last read or wrote. It reports whether it reloaded.
+// A file that can't be parsed leaves the current config in place.
+func reloadIfChanged() (bool, error) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	configPath := GetConfigFilePath()
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		// Deleted, or in the middle of being replaced
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	hash := sha256.Sum256(data)
+	if hash == lastHash {
+		return false, nil
+	}
+
+	cfg := &Config{}
+	if err := Unmarshal(data, cfg, FormatFromPath(configPath)); err != nil {
+		return false, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	globalConfig = cfg
+	lastHash = hash
+	loaded = true
+	return true, loadProjectInternal()
+}
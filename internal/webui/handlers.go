@@ -2,13 +2,16 @@ package webui
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"os"
+	"os/exec"
+	"reflect"
+	"strconv"
 
 	"aliasly/internal/alias"
 	"aliasly/internal/config"
-	"go.yaml.in/yaml/v3"
 )
 
 // APIResponse is a standard response format for our API.
@@ -22,10 +25,16 @@ type APIResponse struct {
 
 	// Error contains the error message (if failed)
 	Error string `json:"error,omitempty"`
+
+	// Warnings lists problems that didn't stop the operation,
+	// such as parameters the command never uses
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // handleListAliases handles GET /api/aliases
 // It returns a list of all configured aliases as JSON.
+// The optional ?tag= query parameter filters them by a tag expression,
+// like "git" or "docker or k8s".
 func handleListAliases(w http.ResponseWriter, r *http.Request) {
 	// Get all aliases from config
 	aliases, err := alias.GetAll()
@@ -34,6 +43,23 @@ func handleListAliases(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Filter by tag expression if one was given
+	if tag := r.URL.Query().Get("tag"); tag != "" {
+		expr, err := alias.ParseTagExpr(tag)
+		if err != nil {
+			sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		matched := make([]alias.Alias, 0, len(aliases))
+		for _, a := range aliases {
+			if expr.Match(a.Tags) {
+				matched = append(matched, a)
+			}
+		}
+		aliases = matched
+	}
+
 	// Send success response with aliases
 	sendJSON(w, http.StatusOK, APIResponse{
 		Success: true,
@@ -56,10 +82,26 @@ func handleCreateAlias(w http.ResponseWriter, r *http.Request) {
 		sendError(w, http.StatusBadRequest, "Alias name is required")
 		return
 	}
+	if err := alias.ValidateName(newAlias.Name); err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if newAlias.Command == "" {
 		sendError(w, http.StatusBadRequest, "Command is required")
 		return
 	}
+	if err := alias.ValidateVariadic(newAlias); err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := alias.ValidateConstraints(newAlias); err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := alias.CheckPlaceholders(newAlias); err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	// Check if alias already exists
 	if _, exists := alias.Find(newAlias.Name); exists {
@@ -69,14 +111,15 @@ func handleCreateAlias(w http.ResponseWriter, r *http.Request) {
 
 	// Add the alias
 	if err := alias.Add(newAlias); err != nil {
-		sendError(w, http.StatusInternalServerError, err.Error())
+		sendError(w, saveErrorStatus(err), err.Error())
 		return
 	}
 
 	// Return the created alias
 	sendJSON(w, http.StatusCreated, APIResponse{
-		Success: true,
-		Data:    newAlias,
+		Success:  true,
+		Data:     newAlias,
+		Warnings: unusedParamWarnings(newAlias),
 	})
 }
 
@@ -112,20 +155,136 @@ func handleUpdateAlias(w http.ResponseWriter, r *http.Request) {
 		sendError(w, http.StatusBadRequest, "Command is required")
 		return
 	}
+	if err := alias.ValidateVariadic(updatedAlias); err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := alias.ValidateConstraints(updatedAlias); err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := alias.CheckPlaceholders(updatedAlias); err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	// Update the alias
 	if err := alias.Update(updatedAlias); err != nil {
-		sendError(w, http.StatusInternalServerError, err.Error())
+		sendError(w, saveErrorStatus(err), err.Error())
 		return
 	}
 
 	// Return the updated alias
 	sendJSON(w, http.StatusOK, APIResponse{
-		Success: true,
-		Data:    updatedAlias,
+		Success:  true,
+		Data:     updatedAlias,
+		Warnings: unusedParamWarnings(updatedAlias),
+	})
+}
+
+// handlePatchAlias handles PATCH /api/aliases/{name}
+// It updates only the fields present in the JSON request body, leaving
+// the others as they are, e.g. {"description": "new text"}.
+// A field given in the body replaces the old value entirely, including
+// lists like params and tags and the env map.
+func handlePatchAlias(w http.ResponseWriter, r *http.Request) {
+	aliasName := r.PathValue("name")
+	if aliasName == "" {
+		sendError(w, http.StatusBadRequest, "Alias name is required in URL")
+		return
+	}
+
+	existing, exists := alias.Find(aliasName)
+	if !exists {
+		sendError(w, http.StatusNotFound, "Alias '"+aliasName+"' not found")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "Failed to read request body: "+err.Error())
+		return
+	}
+
+	// Check which fields were given, so slices and maps are replaced
+	// rather than merged element by element
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	// Decoding onto a copy of the existing alias only overwrites the
+	// given fields, and leaves the loaded config alone until it's saved
+	patched := existing.Clone()
+	if _, ok := fields["params"]; ok {
+		patched.Params = nil
+	}
+	if _, ok := fields["tags"]; ok {
+		patched.Tags = nil
+	}
+	if _, ok := fields["env"]; ok {
+		patched.Env = nil
+	}
+	if err := json.Unmarshal(body, &patched); err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	// The name can't be changed through a patch
+	patched.Name = aliasName
+
+	// Validate the result like a full update
+	if patched.Command == "" {
+		sendError(w, http.StatusBadRequest, "Command is required")
+		return
+	}
+	if err := alias.ValidateVariadic(patched); err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := alias.ValidateConstraints(patched); err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := alias.CheckPlaceholders(patched); err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := alias.Update(patched); err != nil {
+		sendError(w, saveErrorStatus(err), err.Error())
+		return
+	}
+
+	// Return the merged alias
+	sendJSON(w, http.StatusOK, APIResponse{
+		Success:  true,
+		Data:     patched,
+		Warnings: unusedParamWarnings(patched),
 	})
 }
 
+// saveErrorStatus returns the HTTP status for an error from saving an
+// alias: 409 Conflict for aliases from the project config, which can't
+// be changed here, and 500 for anything else.
+func saveErrorStatus(err error) int {
+	if errors.Is(err, config.ErrProjectAlias) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// unusedParamWarnings returns a warning for each parameter the alias
+// declares but never uses.
+func unusedParamWarnings(a config.Alias) []string {
+	var warnings []string
+	for _, name := range alias.UnusedParams(a) {
+		warnings = append(warnings, "Parameter '"+name+"' is not used in the command")
+	}
+	return warnings
+}
+
 // handleDeleteAlias handles DELETE /api/aliases/{name}
 // It deletes an existing alias.
 func handleDeleteAlias(w http.ResponseWriter, r *http.Request) {
@@ -144,7 +303,7 @@ func handleDeleteAlias(w http.ResponseWriter, r *http.Request) {
 
 	// Delete the alias
 	if err := alias.Remove(aliasName); err != nil {
-		sendError(w, http.StatusInternalServerError, err.Error())
+		sendError(w, saveErrorStatus(err), err.Error())
 		return
 	}
 
@@ -154,6 +313,138 @@ func handleDeleteAlias(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// BulkDeleteResult contains the result of a bulk delete.
+type BulkDeleteResult struct {
+	Deleted  []string `json:"deleted"`
+	NotFound []string `json:"not_found"`
+}
+
+// handleBulkDeleteAliases handles POST /api/aliases/bulk-delete
+// It accepts a JSON array of alias names and deletes the ones that exist.
+// Names that don't exist are reported rather than aborting the others,
+// like the import handler skips aliases it can't add.
+func handleBulkDeleteAliases(w http.ResponseWriter, r *http.Request) {
+	var names []string
+	if err := json.NewDecoder(r.Body).Decode(&names); err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid JSON (expected an array of alias names): "+err.Error())
+		return
+	}
+
+	deleted, notFound, err := alias.RemoveMany(names)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Send [] rather than null, so clients can always iterate
+	result := BulkDeleteResult{Deleted: []string{}, NotFound: []string{}}
+	result.Deleted = append(result.Deleted, deleted...)
+	result.NotFound = append(result.NotFound, notFound...)
+
+	sendJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    result,
+	})
+}
+
+// MetaInfo describes server-side rules the frontend needs to know about.
+type MetaInfo struct {
+	// NamePolicy is the configured alias name policy
+	NamePolicy config.NamePolicy `json:"name_policy"`
+
+	// NamePattern is the regex alias names must match (for input validation)
+	NamePattern string `json:"name_pattern"`
+
+	// NameHint is a human-readable description of the naming rules
+	NameHint string `json:"name_hint"`
+
+	// AllowRun is whether aliases can be run from the UI
+	AllowRun bool `json:"allow_run"`
+}
+
+// handleMeta handles GET /api/meta
+// It returns the naming rules so the UI can show matching hints,
+// and which optional features are enabled.
+func (s *Server) handleMeta(w http.ResponseWriter, r *http.Request) {
+	cfg, err := config.Get()
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	policy := cfg.Settings.NamePolicy
+	sendJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data: MetaInfo{
+			NamePolicy:  policy,
+			NamePattern: alias.NamePattern(policy),
+			NameHint:    alias.NameHint(policy),
+			AllowRun:    s.opts.AllowRun,
+		},
+	})
+}
+
+// handleGetSettings handles GET /api/settings
+// It returns the global settings.
+func handleGetSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := config.GetSettings()
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	sendJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    settings,
+	})
+}
+
+// handleUpdateSettings handles PUT /api/settings
+// It updates the global settings with the JSON request body. Fields left
+// out of the body keep their current values.
+func handleUpdateSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := config.GetSettings()
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	if settings.Shell != "" {
+		// LookPath accepts both a path and a name to find in PATH
+		if _, err := exec.LookPath(settings.Shell); err != nil {
+			sendError(w, http.StatusBadRequest, "Shell '"+settings.Shell+"' not found or not executable")
+			return
+		}
+	}
+	if settings.Timeout < 0 {
+		sendError(w, http.StatusBadRequest, "Timeout can't be negative")
+		return
+	}
+	if settings.BackupCount != nil && *settings.BackupCount < 0 {
+		sendError(w, http.StatusBadRequest, "Backup count can't be negative")
+		return
+	}
+	if settings.NamePolicy.MaxLength < 0 {
+		sendError(w, http.StatusBadRequest, "Name max length can't be negative")
+		return
+	}
+
+	if err := config.UpdateSettings(settings); err != nil {
+		sendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	sendJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    settings,
+	})
+}
+
 // sendJSON sends a JSON response with the given status code.
 // This is a helper function to avoid repeating JSON encoding code.
 func sendJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -179,9 +470,10 @@ func sendError(w http.ResponseWriter, status int, message string) {
 }
 
 // handleExportConfig handles GET /api/config/export
-// It returns the full config file as YAML for download.
+// It returns the full config file as YAML (or TOML) for download.
 func handleExportConfig(w http.ResponseWriter, r *http.Request) {
 	configPath := config.GetConfigFilePath()
+	format := config.FormatFromPath(configPath)
 
 	data, err := os.ReadFile(configPath)
 	if err != nil {
@@ -190,37 +482,114 @@ func handleExportConfig(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Set headers for file download
-	w.Header().Set("Content-Type", "application/x-yaml")
-	w.Header().Set("Content-Disposition", "attachment; filename=aliasly-config.yaml")
+	w.Header().Set("Content-Type", "application/x-"+format)
+	w.Header().Set("Content-Disposition", "attachment; filename=aliasly-config."+format)
 	w.WriteHeader(http.StatusOK)
 	w.Write(data)
 }
 
 // ImportResult contains the result of an import operation.
 type ImportResult struct {
-	Added    int      `json:"added"`
-	Skipped  int      `json:"skipped"`
-	Aliases  []config.Alias `json:"aliases"`
+	Added     int            `json:"added"`
+	Updated   int            `json:"updated"`
+	Unchanged int            `json:"unchanged"`
+	Skipped   int            `json:"skipped"`
+	Aliases   []config.Alias `json:"aliases"`
+
+	// Rejected lists the aliases that couldn't be saved, e.g. because
+	// of undefined placeholders. They are counted in Skipped.
+	Rejected []string `json:"rejected,omitempty"`
 }
 
 // handleImportConfig handles POST /api/config/import
-// It accepts a YAML file and merges new aliases with existing ones.
-// Existing aliases with the same name are skipped (not replaced).
+// It accepts a YAML or TOML file and merges new aliases with existing ones.
+// Existing aliases with the same name are skipped (not replaced), unless
+// the "overwrite" form field is true.
 func handleImportConfig(w http.ResponseWriter, r *http.Request) {
+	importedConfig, ok := readUploadedConfig(w, r)
+	if !ok {
+		return
+	}
+
+	// Get current aliases to check for duplicates
+	currentAliases, err := alias.GetAll()
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to load current config: "+err.Error())
+		return
+	}
+
+	// Build map of existing aliases
+	existing := make(map[string]config.Alias)
+	for _, a := range currentAliases {
+		existing[a.Name] = a
+	}
+
+	overwrite, _ := strconv.ParseBool(r.FormValue("overwrite"))
+
+	// Merge: add new aliases, and replace changed ones if overwriting
+	added, updated, unchanged, skipped := 0, 0, 0, 0
+	var rejected []string
+	for _, a := range importedConfig.Aliases {
+		current, exists := existing[a.Name]
+		switch {
+		case exists && reflect.DeepEqual(current, a):
+			unchanged++
+		case exists && !overwrite:
+			skipped++
+		case exists:
+			// Saved like any other edit, so broken aliases are rejected
+			if err := alias.Update(a); err != nil {
+				// Skip on error but continue with others
+				skipped++
+				rejected = append(rejected, a.Name)
+				continue
+			}
+			updated++
+		default:
+			if err := alias.Add(a); err != nil {
+				// Skip on error but continue with others
+				skipped++
+				rejected = append(rejected, a.Name)
+				continue
+			}
+			added++
+		}
+	}
+
+	// Get updated aliases
+	allAliases, _ := alias.GetAll()
+
+	sendJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data: ImportResult{
+			Added:     added,
+			Updated:   updated,
+			Unchanged: unchanged,
+			Skipped:   skipped,
+			Aliases:   allAliases,
+			Rejected:  rejected,
+		},
+	})
+}
+
+// readUploadedConfig reads and parses the config file uploaded in the
+// "config" form field. On failure it sends an error response and
+// returns false.
+func readUploadedConfig(w http.ResponseWriter, r *http.Request) (*config.Config, bool) {
 	// Limit upload size to 1MB
 	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
 
 	// Parse multipart form
 	if err := r.ParseMultipartForm(1 << 20); err != nil {
 		sendError(w, http.StatusBadRequest, "Failed to parse form: "+err.Error())
-		return
+		return nil, false
 	}
 
 	// Get the uploaded file
-	file, _, err := r.FormFile("config")
+	file, header, err := r.FormFile("config")
 	if err != nil {
 		sendError(w, http.StatusBadRequest, "No file uploaded: "+err.Error())
-		return
+		return nil, false
 	}
 	defer file.Close()
 
@@ -228,54 +597,53 @@ func handleImportConfig(w http.ResponseWriter, r *http.Request) {
 	data, err := io.ReadAll(file)
 	if err != nil {
 		sendError(w, http.StatusInternalServerError, "Failed to read file: "+err.Error())
-		return
+		return nil, false
 	}
 
-	// Validate YAML structure
-	var importedConfig config.Config
-	if err := yaml.Unmarshal(data, &importedConfig); err != nil {
-		sendError(w, http.StatusBadRequest, "Invalid YAML format: "+err.Error())
-		return
+	// Validate the file is actually an aliasly config
+	cfg, err := config.ParseFormat(data, config.FormatFromPath(header.Filename))
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return nil, false
 	}
 
-	// Get current aliases to check for duplicates
-	currentAliases, err := alias.GetAll()
-	if err != nil {
-		sendError(w, http.StatusInternalServerError, "Failed to load current config: "+err.Error())
+	return cfg, true
+}
+
+// ValidationResult contains the problems found in an uploaded config.
+type ValidationResult struct {
+	Valid    bool          `json:"valid"`
+	Errors   int           `json:"errors"`
+	Warnings int           `json:"warnings"`
+	Issues   []alias.Issue `json:"issues"`
+}
+
+// handleValidateConfig handles POST /api/config/validate
+// It accepts the same upload as the import endpoint and reports the
+// problems `al validate` would find, without changing the config.
+// The config is valid if there are no errors; warnings are allowed.
+func handleValidateConfig(w http.ResponseWriter, r *http.Request) {
+	cfg, ok := readUploadedConfig(w, r)
+	if !ok {
 		return
 	}
 
-	// Build map of existing alias names
-	existing := make(map[string]bool)
-	for _, a := range currentAliases {
-		existing[a.Name] = true
+	result := ValidationResult{Issues: alias.ValidateConfig(cfg)}
+	if result.Issues == nil {
+		// Send [] rather than null, so clients can always iterate
+		result.Issues = []alias.Issue{}
 	}
-
-	// Merge: add only new aliases
-	added := 0
-	skipped := 0
-	for _, a := range importedConfig.Aliases {
-		if existing[a.Name] {
-			skipped++
-			continue
-		}
-		if err := config.AddAlias(a); err != nil {
-			// Skip on error but continue with others
-			skipped++
-			continue
+	for _, issue := range result.Issues {
+		if issue.IsError() {
+			result.Errors++
+		} else {
+			result.Warnings++
 		}
-		added++
 	}
-
-	// Get updated aliases
-	allAliases, _ := alias.GetAll()
+	result.Valid = result.Errors == 0
 
 	sendJSON(w, http.StatusOK, APIResponse{
 		Success: true,
-		Data: ImportResult{
-			Added:   added,
-			Skipped: skipped,
-			Aliases: allAliases,
-		},
+		Data:    result,
 	})
 }
@@ -0,0 +1,164 @@
+package webui
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"aliasly/internal/config"
+)
+
+// testConfig is a config with one parameterized alias, used by the
+// handler tests.
+const testConfig = `version: 1
+settings:
+  shell: /bin/sh
+aliases:
+  - name: greet
+    command: echo {{msg}}
+    description: Say something
+    params:
+      - name: msg
+        description: What to say
+        default: hi
+        choices: [hi, hello]
+    tags: [demo]
+`
+
+// loadTestConfig points aliasly at a temporary config directory holding
+// data and loads it.
+func loadTestConfig(t *testing.T, data string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("ALIASLY_CONFIG_DIR", filepath.Join(dir, "config"))
+	t.Setenv("ALIASLY_STATE_DIR", filepath.Join(dir, "state"))
+
+	if err := os.MkdirAll(config.GetConfigDir(), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(config.GetConfigFilePath(), []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Load(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// patchAlias sends a PATCH request for the named alias to the handler
+// and returns the recorded response.
+func patchAlias(t *testing.T, name, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/aliases/"+name, strings.NewReader(body))
+	req.SetPathValue("name", name)
+	rec := httptest.NewRecorder()
+	handlePatchAlias(rec, req)
+	return rec
+}
+
+func TestPatchAliasReplacesParams(t *testing.T) {
+	loadTestConfig(t, testConfig)
+
+	rec := patchAlias(t, "greet", `{"params":[{"name":"msg","description":"Message"}]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+
+	a, _ := config.FindAlias("greet")
+	want := []config.Param{{Name: "msg", Description: "Message"}}
+	if !reflect.DeepEqual(a.Params, want) {
+		t.Errorf("params = %+v, want %+v (old default or choices kept)", a.Params, want)
+	}
+	if !reflect.DeepEqual(a.Tags, []string{"demo"}) {
+		t.Errorf("tags = %v, want them unchanged", a.Tags)
+	}
+}
+
+func TestPatchAliasRejectedLeavesConfigUnchanged(t *testing.T) {
+	loadTestConfig(t, testConfig)
+
+	before, _ := config.FindAlias("greet")
+	before = before.Clone()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"undefined placeholder", `{"params":[{"name":"other","choices":["x"]}]}`},
+		{"variadic not last", `{"command":"echo {{msg}} {{b}}","params":[{"name":"msg","variadic":true},{"name":"b"}]}`},
+		{"bad tags", `{"tags":["changed"],"command":""}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := patchAlias(t, "greet", tt.body)
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusBadRequest, rec.Body)
+			}
+
+			cfg, err := config.Get()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var after config.Alias
+			for _, a := range cfg.Aliases {
+				if a.Name == "greet" {
+					after = a
+				}
+			}
+			if !reflect.DeepEqual(after, before) {
+				t.Errorf("alias changed by a rejected patch:\n got %+v\nwant %+v", after, before)
+			}
+		})
+	}
+}
+
+func TestProjectAliasConflict(t *testing.T) {
+	projectDir := t.TempDir()
+	project := "aliases:\n  - name: proj\n    command: echo project\n"
+	if err := os.WriteFile(filepath.Join(projectDir, config.ProjectConfigFileName), []byte(project), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(projectDir)
+	loadTestConfig(t, strings.Replace(testConfig, "settings:\n", "settings:\n  project_config: true\n", 1))
+
+	tests := []struct {
+		name    string
+		method  string
+		body    string
+		handler http.HandlerFunc
+	}{
+		{"update", http.MethodPut, `{"command":"echo changed"}`, handleUpdateAlias},
+		{"patch", http.MethodPatch, `{"description":"changed"}`, handlePatchAlias},
+		{"delete", http.MethodDelete, "", handleDeleteAlias},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/aliases/proj", strings.NewReader(tt.body))
+			req.SetPathValue("name", "proj")
+			rec := httptest.NewRecorder()
+			tt.handler(rec, req)
+
+			if rec.Code != http.StatusConflict {
+				t.Errorf("status = %d, want %d: %s", rec.Code, http.StatusConflict, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), `"error"`) {
+				t.Errorf("body has no JSON error: %s", rec.Body)
+			}
+		})
+	}
+
+	t.Run("add global alias with the same name", func(t *testing.T) {
+		err := config.AddAlias(config.Alias{Name: "proj", Command: "echo global"})
+		if !errors.Is(err, config.ErrProjectAlias) {
+			t.Errorf("AddAlias error = %v, want ErrProjectAlias", err)
+		}
+	})
+}
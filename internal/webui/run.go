@@ -0,0 +1,148 @@
+package webui
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"aliasly/internal/alias"
+)
+
+// RunRequest is the body of a request to run an alias.
+type RunRequest struct {
+	// Params maps parameter names to their values
+	Params map[string]string `json:"params"`
+
+	// DryRun, when true, only returns the expanded command
+	DryRun bool `json:"dry_run"`
+
+	// Yes, when true, runs aliases marked with confirm and commands
+	// that match one of the confirm_patterns settings, which are
+	// refused otherwise
+	Yes bool `json:"yes"`
+}
+
+// RunEvent is one line of the streamed response from running an alias.
+// Output events have Stream and Data set; the last event has either
+// ExitCode or Error set.
+type RunEvent struct {
+	// Stream is "stdout" or "stderr" for output events
+	Stream string `json:"stream,omitempty"`
+
+	// Data is a chunk of output
+	Data string `json:"data,omitempty"`
+
+	// ExitCode is the command's exit code, sent when it finishes
+	ExitCode *int `json:"exit_code,omitempty"`
+
+	// Error describes why the command couldn't be run
+	Error string `json:"error,omitempty"`
+}
+
+// eventWriter writes RunEvents as newline-delimited JSON and flushes
+// each one, so the browser sees output as it's produced.
+// The command's stdout and stderr are copied concurrently, so writes
+// are serialized with a mutex.
+type eventWriter struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	encoder *json.Encoder
+}
+
+// send writes a single event to the response.
+func (e *eventWriter) send(event RunEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.encoder.Encode(event)
+	if flusher, ok := e.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// streamWriter is an io.Writer that sends everything written to it
+// as output events for one stream.
+type streamWriter struct {
+	events *eventWriter
+	stream string
+}
+
+// Write implements io.Writer.
+func (s streamWriter) Write(p []byte) (int, error) {
+	s.events.send(RunEvent{Stream: s.stream, Data: string(p)})
+	return len(p), nil
+}
+
+// handleRunAlias handles POST /api/aliases/{name}/run
+// It runs an alias with the given parameter values and streams back
+// its output as newline-delimited JSON RunEvents, ending with the exit
+// code. It is disabled unless the server was started with AllowRun.
+func (s *Server) handleRunAlias(w http.ResponseWriter, r *http.Request) {
+	if !s.opts.AllowRun {
+		sendError(w, http.StatusForbidden, "Running aliases is disabled; start the UI with 'al config --allow-run'")
+		return
+	}
+
+	aliasName := r.PathValue("name")
+	a, found := alias.Find(aliasName)
+	if !found {
+		sendError(w, http.StatusNotFound, "Alias '"+aliasName+"' not found")
+		return
+	}
+
+	var req RunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	// Pass the values as --name=value so they can't be mistaken
+	// for positional arguments
+	declared := make(map[string]bool, len(a.Params))
+	for _, p := range a.Params {
+		declared[p.Name] = true
+	}
+	args := make([]string, 0, len(req.Params))
+	for name, value := range req.Params {
+		if !declared[name] {
+			sendError(w, http.StatusBadRequest, "Unknown parameter: "+name)
+			return
+		}
+		args = append(args, "--"+name+"="+value)
+	}
+
+	// Report problems like missing params as a normal error response,
+	// before any output has been streamed
+	if _, err := alias.ParseCommand(a, args); err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Like the CLI, aliases marked as dangerous need an explicit yes,
+	// which the UI only sends after asking
+	if a.Confirm && !req.Yes && !req.DryRun {
+		sendError(w, http.StatusConflict, "Alias '"+aliasName+"' asks for confirmation before running; send \"yes\": true to run it")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	events := &eventWriter{w: w, encoder: json.NewEncoder(w)}
+	exitCode, err := alias.RunWithOptions(a, args, alias.ExecuteOptions{
+		DryRun:      req.DryRun,
+		SkipConfirm: req.Yes,
+		Stdin:       strings.NewReader(""),
+		Stdout:      streamWriter{events: events, stream: "stdout"},
+		Stderr:      streamWriter{events: events, stream: "stderr"},
+		// Stop the command if the browser goes away
+		Context: r.Context(),
+	})
+	if err != nil {
+		events.send(RunEvent{Error: err.Error()})
+		return
+	}
+
+	events.send(RunEvent{ExitCode: &exitCode})
+}
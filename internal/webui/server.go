@@ -3,8 +3,13 @@
 package webui
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
 	"io/fs"
 	"net/http"
+	"strings"
 
 	"aliasly/web"
 )
@@ -16,13 +21,34 @@ type Server struct {
 	// mux is the HTTP request multiplexer (router)
 	// It routes incoming requests to the appropriate handlers
 	mux *http.ServeMux
+
+	// opts holds the settings the server was created with
+	opts Options
+}
+
+// Options configures a web UI server.
+type Options struct {
+	// Token, if not empty, must be sent with every API request,
+	// either as an "Authorization: Bearer <token>" header or as
+	// a ?token= query parameter
+	Token string
+
+	// AllowRun, when true, enables the endpoint that runs aliases.
+	// It is off by default, since it lets the UI execute commands.
+	AllowRun bool
+
+	// CORSOrigins lists the origins that may call the API from other
+	// web pages, e.g. "http://localhost:3000", or "*" for any origin.
+	// It is empty by default, so browsers block cross-origin requests.
+	CORSOrigins []string
 }
 
 // NewServer creates a new web UI server instance.
 // It sets up all routes and handlers.
-func NewServer() *Server {
+func NewServer(opts Options) *Server {
 	s := &Server{
-		mux: http.NewServeMux(),
+		mux:  http.NewServeMux(),
+		opts: opts,
 	}
 
 	// Set up routes
@@ -34,7 +60,92 @@ func NewServer() *Server {
 // Handler returns the HTTP handler for this server.
 // This is used by the http.Server to handle incoming requests.
 func (s *Server) Handler() http.Handler {
-	return s.mux
+	var handler http.Handler = s.mux
+	if s.opts.Token != "" {
+		handler = s.requireToken(handler)
+	}
+	if len(s.opts.CORSOrigins) > 0 {
+		// Outermost, since preflight requests carry no token
+		handler = s.cors(handler)
+	}
+	return handler
+}
+
+// cors wraps next so that API requests from the allowed origins get
+// CORS headers, and answers their OPTIONS preflight requests with the
+// methods registered for the path. Requests from other origins get no
+// CORS headers, so browsers keep blocking them.
+func (s *Server) cors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if !strings.HasPrefix(r.URL.Path, "/api/") || origin == "" || !s.originAllowed(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Add("Vary", "Origin")
+
+		if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		allowed := s.allowedMethods(r)
+		if len(allowed) == 0 {
+			sendError(w, http.StatusNotFound, "Unknown API endpoint: "+r.URL.Path)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(allowed, ", "))
+		w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+		w.Header().Set("Access-Control-Max-Age", "600")
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// originAllowed reports whether origin is one of the CORS origins.
+func (s *Server) originAllowed(origin string) bool {
+	for _, allowed := range s.opts.CORSOrigins {
+		if allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// requireToken wraps next so that requests to /api/ are rejected unless
+// they carry the server's token. The static files stay public, since
+// they contain no data; the page reads the token from its URL.
+func (s *Server) requireToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token := r.URL.Query().Get("token")
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			token = strings.TrimPrefix(auth, "Bearer ")
+		}
+
+		// Compare in constant time so the token can't be guessed byte by byte
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) != 1 {
+			sendError(w, http.StatusUnauthorized, "Missing or invalid token")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// GenerateToken returns a random token for protecting the API.
+func GenerateToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
 }
 
 // setupRoutes configures all the URL routes for the server.
@@ -51,15 +162,40 @@ func (s *Server) setupRoutes() {
 	// PUT /api/aliases/{name} - Update an existing alias
 	s.mux.HandleFunc("PUT /api/aliases/{name}", handleUpdateAlias)
 
+	// PATCH /api/aliases/{name} - Update only the given fields of an alias
+	s.mux.HandleFunc("PATCH /api/aliases/{name}", handlePatchAlias)
+
 	// DELETE /api/aliases/{name} - Delete an alias
 	s.mux.HandleFunc("DELETE /api/aliases/{name}", handleDeleteAlias)
 
+	// POST /api/aliases/bulk-delete - Delete several aliases at once
+	s.mux.HandleFunc("POST /api/aliases/bulk-delete", handleBulkDeleteAliases)
+
+	// POST /api/aliases/{name}/run - Run an alias (only with AllowRun)
+	s.mux.HandleFunc("POST /api/aliases/{name}/run", s.handleRunAlias)
+
+	// GET /api/meta - Naming rules and other info for the frontend
+	s.mux.HandleFunc("GET /api/meta", s.handleMeta)
+
+	// GET /api/settings - Get the global settings
+	s.mux.HandleFunc("GET /api/settings", handleGetSettings)
+
+	// PUT /api/settings - Update the global settings
+	s.mux.HandleFunc("PUT /api/settings", handleUpdateSettings)
+
 	// GET /api/config/export - Export config as YAML file
 	s.mux.HandleFunc("GET /api/config/export", handleExportConfig)
 
 	// POST /api/config/import - Import config from YAML file
 	s.mux.HandleFunc("POST /api/config/import", handleImportConfig)
 
+	// POST /api/config/validate - Check an uploaded config without importing it
+	s.mux.HandleFunc("POST /api/config/validate", handleValidateConfig)
+
+	// Any other /api/ request gets a JSON 404 or 405, rather than
+	// falling through to the static file server and returning HTML
+	s.mux.HandleFunc(apiCatchAllPattern, s.handleAPINotFound)
+
 	// Serve static files (HTML, CSS, JS)
 	// We need to strip the "static" prefix because the files are
 	// embedded under "static/" but we want to serve them from "/"
@@ -74,3 +210,43 @@ func (s *Server) setupRoutes() {
 	fileServer := http.FileServer(http.FS(staticFS))
 	s.mux.Handle("/", fileServer)
 }
+
+// apiCatchAllPattern matches every /api/ request that no API route handles.
+const apiCatchAllPattern = "/api/"
+
+// apiMethods are the HTTP methods the API routes use.
+var apiMethods = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+}
+
+// handleAPINotFound handles /api/ requests that match no route.
+// If the path exists with other methods, it returns 405 Method Not
+// Allowed with an Allow header; otherwise 404 Not Found. Both use the
+// usual JSON error format.
+func (s *Server) handleAPINotFound(w http.ResponseWriter, r *http.Request) {
+	if allowed := s.allowedMethods(r); len(allowed) > 0 {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		sendError(w, http.StatusMethodNotAllowed, "Method "+r.Method+" not allowed for "+r.URL.Path)
+		return
+	}
+
+	sendError(w, http.StatusNotFound, "Unknown API endpoint: "+r.URL.Path)
+}
+
+// allowedMethods returns the methods that have an API route for the
+// request's path, by asking the router which ones would match.
+func (s *Server) allowedMethods(r *http.Request) []string {
+	var allowed []string
+	for _, method := range apiMethods {
+		probe := r.Clone(r.Context())
+		probe.Method = method
+		if _, pattern := s.mux.Handler(probe); pattern != "" && pattern != apiCatchAllPattern {
+			allowed = append(allowed, method)
+		}
+	}
+	return allowed
+}
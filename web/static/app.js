@@ -11,12 +11,40 @@
 // API Functions
 // ============================================
 
+/**
+ * The token the server requires on API requests.
+ * It is passed in the page URL (?token=...) when `al config` opens the
+ * browser, and kept for the session so reloads keep working.
+ */
+const apiToken = (() => {
+    const token = new URLSearchParams(window.location.search).get('token');
+    if (token) {
+        sessionStorage.setItem('aliaslyToken', token);
+        return token;
+    }
+    return sessionStorage.getItem('aliaslyToken') || '';
+})();
+
+/**
+ * Calls fetch with the API token added as an Authorization header.
+ * @param {string} url - The API URL
+ * @param {Object} options - fetch options
+ * @returns {Promise<Response>} The fetch response
+ */
+function apiFetch(url, options = {}) {
+    const headers = Object.assign({}, options.headers);
+    if (apiToken) {
+        headers['Authorization'] = 'Bearer ' + apiToken;
+    }
+    return fetch(url, Object.assign({}, options, { headers }));
+}
+
 /**
  * Fetches all aliases from the server.
  * @returns {Promise<Array>} Array of alias objects
  */
 async function fetchAliases() {
-    const response = await fetch('/api/aliases');
+    const response = await apiFetch('/api/aliases');
     const result = await response.json();
 
     if (!result.success) {
@@ -32,7 +60,7 @@ async function fetchAliases() {
  * @returns {Promise<Object>} The created alias
  */
 async function createAlias(alias) {
-    const response = await fetch('/api/aliases', {
+    const response = await apiFetch('/api/aliases', {
         method: 'POST',
         headers: { 'Content-Type': 'application/json' },
         body: JSON.stringify(alias)
@@ -44,6 +72,7 @@ async function createAlias(alias) {
         throw new Error(result.error || 'Failed to create alias');
     }
 
+    showWarnings(result.warnings);
     return result.data;
 }
 
@@ -54,7 +83,7 @@ async function createAlias(alias) {
  * @returns {Promise<Object>} The updated alias
  */
 async function updateAlias(name, alias) {
-    const response = await fetch(`/api/aliases/${encodeURIComponent(name)}`, {
+    const response = await apiFetch(`/api/aliases/${encodeURIComponent(name)}`, {
         method: 'PUT',
         headers: { 'Content-Type': 'application/json' },
         body: JSON.stringify(alias)
@@ -66,15 +95,27 @@ async function updateAlias(name, alias) {
         throw new Error(result.error || 'Failed to update alias');
     }
 
+    showWarnings(result.warnings);
     return result.data;
 }
 
+/**
+ * Shows any warnings returned with a successful response,
+ * such as parameters the command never uses.
+ * @param {Array<string>|undefined} warnings - Warning messages
+ */
+function showWarnings(warnings) {
+    if (warnings && warnings.length > 0) {
+        alert('Saved with warnings:\n\n' + warnings.join('\n'));
+    }
+}
+
 /**
  * Deletes an alias from the server.
  * @param {string} name - The name of the alias to delete
  */
 async function deleteAlias(name) {
-    const response = await fetch(`/api/aliases/${encodeURIComponent(name)}`, {
+    const response = await apiFetch(`/api/aliases/${encodeURIComponent(name)}`, {
         method: 'DELETE'
     });
 
@@ -85,6 +126,46 @@ async function deleteAlias(name) {
     }
 }
 
+/**
+ * Fetches server metadata such as the alias name policy.
+ * @returns {Promise<Object>} Metadata object
+ */
+async function fetchMeta() {
+    const response = await apiFetch('/api/meta');
+    const result = await response.json();
+
+    if (!result.success) {
+        throw new Error(result.error || 'Failed to fetch metadata');
+    }
+
+    return result.data;
+}
+
+// Whether the server allows running aliases ('al config --allow-run')
+let runAllowed = false;
+
+/**
+ * Loads server metadata: applies the configured name policy to the
+ * alias name input and notes whether aliases can be run.
+ */
+async function loadMeta() {
+    try {
+        const meta = await fetchMeta();
+        runAllowed = meta.allow_run;
+
+        const input = document.getElementById('aliasName');
+
+        input.pattern = meta.name_pattern;
+        if (meta.name_policy.max_length > 0) {
+            input.maxLength = meta.name_policy.max_length;
+        }
+        document.getElementById('aliasNameHint').textContent = meta.name_hint;
+    } catch (error) {
+        // Keep the built-in defaults from the HTML
+        console.error('Failed to load name policy:', error);
+    }
+}
+
 // ============================================
 // UI Rendering (Using safe DOM methods)
 // ============================================
@@ -143,6 +224,15 @@ function createAliasCard(alias) {
     const actions = document.createElement('div');
     actions.className = 'alias-actions';
 
+    if (runAllowed) {
+        const runBtn = document.createElement('button');
+        runBtn.className = 'btn-icon';
+        runBtn.title = 'Run';
+        runBtn.onclick = () => openRunModal(alias);
+        runBtn.innerHTML = '<svg width="18" height="18" viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2"><polygon points="5 3 19 12 5 21 5 3"/></svg>';
+        actions.appendChild(runBtn);
+    }
+
     const editBtn = document.createElement('button');
     editBtn.className = 'btn-icon';
     editBtn.title = 'Edit';
@@ -195,6 +285,14 @@ function createAliasCard(alias) {
         card.appendChild(paramsDiv);
     }
 
+    // Tags
+    if (alias.tags && alias.tags.length > 0) {
+        const tagsDiv = document.createElement('div');
+        tagsDiv.className = 'alias-params';
+        tagsDiv.textContent = 'Tags: ' + alias.tags.join(', ');
+        card.appendChild(tagsDiv);
+    }
+
     // Usage
     const usageDiv = document.createElement('div');
     usageDiv.className = 'alias-usage';
@@ -219,10 +317,11 @@ function buildUsageString(alias) {
 
     if (alias.params) {
         for (const p of alias.params) {
+            const name = p.variadic ? `${p.name}...` : p.name;
             if (p.required) {
-                usage += ` <${p.name}>`;
+                usage += ` <${name}>`;
             } else {
-                usage += ` [${p.name}]`;
+                usage += ` [${name}]`;
             }
         }
     }
@@ -272,6 +371,7 @@ async function editAlias(name) {
         document.getElementById('aliasName').disabled = true; // Can't change name
         document.getElementById('aliasCommand').value = alias.command;
         document.getElementById('aliasDescription').value = alias.description || '';
+        document.getElementById('aliasNotes').value = alias.notes || '';
 
         // Populate params
         const paramsContainer = document.getElementById('paramsContainer');
@@ -308,6 +408,201 @@ function confirmDelete(name) {
     document.getElementById('deleteModal').classList.remove('hidden');
 }
 
+/**
+ * Loads the global settings and opens the settings modal.
+ */
+async function openSettingsModal() {
+    try {
+        const response = await apiFetch('/api/settings');
+        const result = await response.json();
+        if (!result.success) {
+            throw new Error(result.error || 'Failed to load settings');
+        }
+
+        const settings = result.data;
+        document.getElementById('settingsShell').value = settings.shell || '';
+        document.getElementById('settingsTimeout').value = settings.timeout || 0;
+        document.getElementById('settingsVerbose').checked = settings.verbose;
+        document.getElementById('settingsQuoteParams').checked = settings.quote_params;
+        document.getElementById('settingsUsePager').checked = settings.use_pager;
+        document.getElementById('settingsWarnOnRoot').checked = settings.warn_on_root;
+        document.getElementById('settingsModal').classList.remove('hidden');
+    } catch (error) {
+        alert('Error loading settings: ' + error.message);
+    }
+}
+
+/**
+ * Closes the settings modal.
+ */
+function closeSettingsModal() {
+    document.getElementById('settingsModal').classList.add('hidden');
+}
+
+/**
+ * Saves the settings form. Settings not shown in the form are left unchanged.
+ * @param {Event} event - The form submit event
+ */
+async function saveSettings(event) {
+    event.preventDefault();
+
+    const settings = {
+        shell: document.getElementById('settingsShell').value.trim(),
+        timeout: parseInt(document.getElementById('settingsTimeout').value, 10) || 0,
+        verbose: document.getElementById('settingsVerbose').checked,
+        quote_params: document.getElementById('settingsQuoteParams').checked,
+        use_pager: document.getElementById('settingsUsePager').checked,
+        warn_on_root: document.getElementById('settingsWarnOnRoot').checked
+    };
+
+    try {
+        const response = await apiFetch('/api/settings', {
+            method: 'PUT',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify(settings)
+        });
+        const result = await response.json();
+        if (!result.success) {
+            throw new Error(result.error || 'Failed to save settings');
+        }
+        closeSettingsModal();
+    } catch (error) {
+        alert('Error saving settings: ' + error.message);
+    }
+}
+
+/**
+ * Opens the run modal for an alias, with an input for each parameter.
+ * @param {Object} alias - The alias to run
+ */
+function openRunModal(alias) {
+    document.getElementById('runAliasName').textContent = alias.name;
+    document.getElementById('runDryRun').checked = false;
+
+    const output = document.getElementById('runOutput');
+    output.textContent = '';
+    output.classList.add('hidden');
+
+    const paramsDiv = document.getElementById('runParams');
+    paramsDiv.textContent = '';
+    for (const p of alias.params || []) {
+        const group = document.createElement('div');
+        group.className = 'form-group';
+
+        const label = document.createElement('label');
+        label.textContent = p.required ? `${p.name} *` : p.name;
+        group.appendChild(label);
+
+        const input = document.createElement('input');
+        input.type = p.secret ? 'password' : 'text';
+        input.dataset.param = p.name;
+        input.placeholder = p.description || '';
+        input.value = p.default || '';
+        group.appendChild(input);
+
+        paramsDiv.appendChild(group);
+    }
+
+    document.getElementById('runAliasBtn').onclick = () => performRun(alias);
+    document.getElementById('runModal').classList.remove('hidden');
+}
+
+/**
+ * Closes the run modal.
+ */
+function closeRunModal() {
+    document.getElementById('runModal').classList.add('hidden');
+}
+
+/**
+ * Appends text to the run output, optionally with a CSS class.
+ * @param {string} text - The text to append
+ * @param {string} className - Optional class for the text
+ */
+function appendRunOutput(text, className) {
+    const output = document.getElementById('runOutput');
+    const span = document.createElement('span');
+    if (className) span.className = className;
+    span.textContent = text;
+    output.appendChild(span);
+    output.scrollTop = output.scrollHeight;
+}
+
+/**
+ * Runs an alias on the server and streams its output into the modal.
+ * The server responds with one JSON event per line (see RunEvent).
+ * @param {Object} alias - The alias to run
+ */
+async function performRun(alias) {
+    const name = alias.name;
+    const params = {};
+    for (const input of document.querySelectorAll('#runParams input')) {
+        if (input.value !== '') {
+            params[input.dataset.param] = input.value;
+        }
+    }
+    const dryRun = document.getElementById('runDryRun').checked;
+
+    // Aliases marked with confirm are only run with an explicit yes,
+    // like the CLI's prompt
+    const question = alias.confirm
+        ? `'${name}' is marked as dangerous. Are you sure you want to run it on this machine?`
+        : `Run '${name}' on this machine?`;
+    if (!dryRun && !confirm(question)) {
+        return;
+    }
+
+    const output = document.getElementById('runOutput');
+    output.textContent = '';
+    output.classList.remove('hidden');
+
+    const runBtn = document.getElementById('runAliasBtn');
+    runBtn.disabled = true;
+
+    try {
+        const response = await apiFetch(`/api/aliases/${encodeURIComponent(name)}/run`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ params, dry_run: dryRun, yes: !dryRun && !!alias.confirm })
+        });
+
+        // Errors before the command starts come back as a normal JSON response
+        if (!response.ok) {
+            const result = await response.json();
+            throw new Error(result.error || 'Failed to run alias');
+        }
+
+        const reader = response.body.getReader();
+        const decoder = new TextDecoder();
+        let buffered = '';
+
+        while (true) {
+            const { done, value } = await reader.read();
+            if (done) break;
+
+            buffered += decoder.decode(value, { stream: true });
+            const lines = buffered.split('\n');
+            buffered = lines.pop();
+
+            for (const line of lines) {
+                if (!line) continue;
+                const event = JSON.parse(line);
+                if (event.error) {
+                    appendRunOutput(`Error: ${event.error}\n`, 'run-error');
+                } else if (event.exit_code !== undefined) {
+                    appendRunOutput(`\n[exit code ${event.exit_code}]\n`, 'run-status');
+                } else {
+                    appendRunOutput(event.data, event.stream === 'stderr' ? 'stderr' : '');
+                }
+            }
+        }
+    } catch (error) {
+        appendRunOutput(`Error: ${error.message}\n`, 'run-error');
+    } finally {
+        runBtn.disabled = false;
+    }
+}
+
 /**
  * Closes the delete confirmation modal.
  */
@@ -472,6 +767,7 @@ async function handleSubmit(event) {
         name: document.getElementById('aliasName').value.trim(),
         command: document.getElementById('aliasCommand').value.trim(),
         description: document.getElementById('aliasDescription').value.trim(),
+        notes: document.getElementById('aliasNotes').value.trim(),
         params: collectParams()
     };
 
@@ -482,7 +778,7 @@ async function handleSubmit(event) {
 
     try {
         if (editingAlias) {
-            await updateAlias(editingAlias.name, alias);
+            await updateAlias(editingAlias.name, preserveFields(editingAlias, alias));
         } else {
             await createAlias(alias);
         }
@@ -494,6 +790,32 @@ async function handleSubmit(event) {
     }
 }
 
+/**
+ * Copies fields the form doesn't edit (e.g. variadic params or usage
+ * stats) from the original alias so saving an edit doesn't drop them.
+ * @param {Object} original - The alias as loaded from the server
+ * @param {Object} edited - The alias built from the form
+ * @returns {Object} The merged alias
+ */
+function preserveFields(original, edited) {
+    const merged = Object.assign({}, original, edited);
+    if (!edited.params) {
+        delete merged.params;
+        return merged;
+    }
+
+    merged.params = edited.params.map(p => {
+        const previous = (original.params || []).find(op => op.name === p.name);
+        if (!previous) return p;
+
+        const param = Object.assign({}, previous, p);
+        if (!p.default) param.default = previous.default || '';
+        return param;
+    });
+
+    return merged;
+}
+
 // ============================================
 // Utility Functions
 // ============================================
@@ -539,7 +861,8 @@ document.addEventListener('DOMContentLoaded', () => {
     // Initialize theme
     initTheme();
 
-    loadAliases();
+    // Load metadata first so the alias cards know whether to show Run
+    loadMeta().then(loadAliases);
 
     // Set up event listeners
     document.getElementById('addAliasBtn').addEventListener('click', () => openAddModal());
@@ -549,6 +872,8 @@ document.addEventListener('DOMContentLoaded', () => {
     document.getElementById('exportBtn').addEventListener('click', exportConfig);
     document.getElementById('importBtn').addEventListener('click', () => document.getElementById('importFileInput').click());
     document.getElementById('importFileInput').addEventListener('change', handleImport);
+    document.getElementById('settingsBtn').addEventListener('click', openSettingsModal);
+    document.getElementById('settingsForm').addEventListener('submit', saveSettings);
 
     // Update preview when command changes (to detect {{params}})
     document.getElementById('aliasCommand').addEventListener('input', () => {
@@ -581,11 +906,17 @@ document.addEventListener('DOMContentLoaded', () => {
         if (e.target.id === 'deleteModal') closeDeleteModal();
     });
 
+    document.getElementById('settingsModal').addEventListener('click', (e) => {
+        if (e.target.id === 'settingsModal') closeSettingsModal();
+    });
+
     // Keyboard shortcuts
     document.addEventListener('keydown', (e) => {
         if (e.key === 'Escape') {
             closeModal();
             closeDeleteModal();
+            closeRunModal();
+            closeSettingsModal();
         }
     });
 });
@@ -654,13 +985,45 @@ function handleSearch(e) {
 function exportConfig() {
     // Create a link to download the config
     const link = document.createElement('a');
-    link.href = '/api/config/export';
-    link.download = 'aliasly-config.yaml';
+    // Links can't send headers, so pass the token in the URL
+    link.href = '/api/config/export?token=' + encodeURIComponent(apiToken);
+    // Leave the name empty so the server picks .yaml or .toml
+    link.download = '';
     document.body.appendChild(link);
     link.click();
     document.body.removeChild(link);
 }
 
+/**
+ * Validates a config file on the server without importing it.
+ * @param {File} file - The config file to check
+ * @returns {Promise<string>} A summary of the problems found, or '' if there are none
+ */
+async function validateImportFile(file) {
+    const formData = new FormData();
+    formData.append('config', file);
+
+    const response = await apiFetch('/api/config/validate', {
+        method: 'POST',
+        body: formData
+    });
+    const result = await response.json();
+    if (!result.success) {
+        throw new Error(result.error || 'Failed to validate config');
+    }
+
+    const issues = result.data.issues;
+    if (issues.length === 0) {
+        return '';
+    }
+
+    const lines = issues.map(issue =>
+        `- ${issue.alias || `alias #${issue.index + 1}`}: ${issue.severity}: ${issue.message}`
+    );
+    return `\n\nThe file has ${result.data.errors} error(s) and ${result.data.warnings} warning(s):\n` +
+        lines.join('\n');
+}
+
 /**
  * Handles file import when user selects a file.
  * @param {Event} event - The file input change event
@@ -669,9 +1032,19 @@ async function handleImport(event) {
     const file = event.target.files[0];
     if (!file) return;
 
+    // Check the file first, so problems are shown before anything changes
+    let problems;
+    try {
+        problems = await validateImportFile(file);
+    } catch (error) {
+        alert('Error importing config: ' + error.message);
+        event.target.value = '';
+        return;
+    }
+
     // Confirm import
     const confirmImport = confirm(
-        `Import "${file.name}"?\n\nNew aliases will be added to your existing configuration.\nAliases with the same name will be skipped.`
+        `Import "${file.name}"?\n\nNew aliases will be added to your existing configuration.` + problems
     );
 
     if (!confirmImport) {
@@ -679,12 +1052,18 @@ async function handleImport(event) {
         return;
     }
 
+    // Existing aliases are kept unless the user wants the file to win
+    const overwrite = confirm(
+        'Overwrite aliases that already exist with the versions from the file?\n\nOK to overwrite them, Cancel to keep your current ones.'
+    );
+
     // Create form data
     const formData = new FormData();
     formData.append('config', file);
+    formData.append('overwrite', overwrite ? 'true' : 'false');
 
     try {
-        const response = await fetch('/api/config/import', {
+        const response = await apiFetch('/api/config/import', {
             method: 'POST',
             body: formData
         });
@@ -702,8 +1081,18 @@ async function handleImport(event) {
 
         // Show result message
         let message = `Import complete!\n\nAdded: ${importResult.added} alias(es)`;
-        if (importResult.skipped > 0) {
-            message += `\nSkipped: ${importResult.skipped} (already exist)`;
+        if (importResult.updated > 0) {
+            message += `\nUpdated: ${importResult.updated}`;
+        }
+        if (importResult.unchanged > 0) {
+            message += `\nUnchanged: ${importResult.unchanged}`;
+        }
+        const rejected = importResult.rejected || [];
+        if (importResult.skipped > rejected.length) {
+            message += `\nSkipped: ${importResult.skipped - rejected.length} (already exist)`;
+        }
+        if (rejected.length > 0) {
+            message += `\nSkipped (invalid, e.g. undefined placeholders): ${rejected.join(', ')}`;
         }
         alert(message);
     } catch (error) {